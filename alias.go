@@ -0,0 +1,175 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// alias maps a deprecated configuration setting (or subtree) name to its new
+// name.
+type alias struct {
+	old string
+	new string
+}
+
+// WithAlias registers the deprecated configuration setting name oldPath as an
+// alias for newPath, so that old configuration files continue to work after
+// renaming a setting. Both names can also refer to subtrees, such as
+// “server” and “http”, in which case all settings inside the subtree are
+// aliased.
+//
+// When looking up newPath (or a setting inside it) and it isn't set, the
+// deprecated oldPath is consulted instead, logging a one-time deprecation
+// warning. When both are set to different values, the lookup fails with a
+// conflict error; values are compared by their textual forms, so that, for
+// instance, “8080” and 8080 are the same.
+func WithAlias(oldPath, newPath string) Option {
+	return func(d *DeafAdder) {
+		d.opts.aliases = append(d.opts.aliases, alias{old: oldPath, new: newPath})
+	}
+}
+
+// WithLogger sets the logger for deprecation warnings, defaulting to
+// slog.Default() if unset.
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *DeafAdder) {
+		d.opts.logger = logger
+	}
+}
+
+// raw returns the raw value for the specified path, consulting any deprecated
+// aliases if necessary. It returns nil if there is no such value.
+func (d *DeafAdder) raw(path string) (any, error) {
+	value, err := d.get(path)
+	if err != nil {
+		return nil, err
+	}
+	o := d.settings()
+	delim := d.Delim()
+	for _, alias := range o.aliases {
+		var oldPath string
+		switch {
+		case path == alias.new:
+			oldPath = alias.old
+		case strings.HasPrefix(path, alias.new+delim):
+			oldPath = alias.old + path[len(alias.new):]
+		default:
+			continue
+		}
+		oldValue, err := d.get(oldPath)
+		if err != nil {
+			return nil, err
+		}
+		if oldValue == nil {
+			continue
+		}
+		if value != nil {
+			if !same(value, oldValue) {
+				return nil, fmt.Errorf("conflicting configuration settings %s and deprecated %s",
+					path, oldPath)
+			}
+			continue
+		}
+		o.deprecated(oldPath, path)
+		value = oldValue
+	}
+	return value, nil
+}
+
+// same returns true if the specified raw values are the same when comparing
+// scalar values by their textual forms, such as “8080” and 8080, and lists and
+// maps element by element, such as []any and []string lists.
+func same(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ma, aIsMap := a.(map[string]any)
+	mb, bIsMap := b.(map[string]any)
+	if aIsMap || bIsMap {
+		if !aIsMap || !bIsMap || len(ma) != len(mb) {
+			return false
+		}
+		for key, value := range ma {
+			other, ok := mb[key]
+			if !ok || !same(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	aIsSlice, bIsSlice := va.Kind() == reflect.Slice, vb.Kind() == reflect.Slice
+	if aIsSlice || bIsSlice {
+		if !aIsSlice || !bIsSlice || va.Len() != vb.Len() {
+			return false
+		}
+		for idx := range va.Len() {
+			if !same(va.Index(idx).Interface(), vb.Index(idx).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// deprecated logs a deprecation warning for the specified old path, but only
+// once.
+func (o *options) deprecated(oldPath, newPath string) {
+	o.mu.Lock()
+	if o.warned[oldPath] {
+		o.mu.Unlock()
+		return
+	}
+	if o.warned == nil {
+		o.warned = map[string]bool{}
+	}
+	o.warned[oldPath] = true
+	o.mu.Unlock()
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("deprecated configuration setting, please use new name instead",
+		slog.String("deprecated", oldPath), slog.String("new", newPath))
+}
+
+// canonical returns the canonical name of the specified absolute path,
+// normalizing it if enabled and replacing deprecated aliases with their new
+// names, such as for matching configuration keys against marked secrets.
+func (d *DeafAdder) canonical(path string) string {
+	o := d.settings()
+	normalize := func(path string) string {
+		if o.normalize == nil {
+			return path
+		}
+		return d.normalizePath(path)
+	}
+	path = normalize(path)
+	delim := d.Delim()
+	for _, alias := range o.aliases {
+		oldPath, newPath := normalize(alias.old), normalize(alias.new)
+		if path == oldPath ||
+			strings.HasPrefix(path, oldPath+delim) ||
+			strings.HasPrefix(path, oldPath+"[") {
+			path = newPath + path[len(oldPath):]
+		}
+	}
+	return path
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("aliases", func() {
+
+	const s = `
+server:
+  addr: 127.0.0.1:80
+  timeout: 10s
+http:
+  port: 8080
+legacy:
+  port: 8080
+  host: localhost
+conflict:
+  old: foo
+  new: bar
+url: http://${server.listen}
+`
+
+	loadWith := func(opts ...Option) *DeafAdder {
+		GinkgoHelper()
+		d := New(koanf.New("."), opts...)
+		Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+		return d
+	}
+
+	It("consults deprecated settings and warns once", func() {
+		var buf bytes.Buffer
+		d := loadWith(
+			WithAlias("server.addr", "server.listen"),
+			WithAlias("legacy", "http"),
+			WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+			WithInterpolation())
+		Expect(d.GetString("server.listen")).To(Equal("127.0.0.1:80"))
+		Expect(d.GetString("server.listen")).To(Equal("127.0.0.1:80"))
+		Expect(d.GetString("url")).To(Equal("http://127.0.0.1:80"))
+		Expect(strings.Count(buf.String(), "deprecated=server.addr new=server.listen")).To(Equal(1))
+
+		Expect(d.GetInt("http.port")).To(Equal(8080))
+		Expect(d.GetString("http.host")).To(Equal("localhost"))
+		Expect(buf.String()).To(ContainSubstring("deprecated=legacy.host new=http.host"))
+		Expect(buf.String()).NotTo(ContainSubstring("deprecated=legacy.port"))
+
+		Expect(d.GetString("server.nada")).Error().To(MatchError(ContainSubstring(
+			"no such configuration setting server.nada")))
+	})
+
+	It("reports conflicts", func() {
+		d := loadWith(WithAlias("conflict.old", "conflict.new"))
+		Expect(d.GetString("conflict.new")).Error().To(MatchError(
+			"conflicting configuration settings conflict.new and deprecated conflict.old"))
+	})
+
+	It("doesn't report the same values of different types as conflicts", func() {
+		d := New(koanf.New("."),
+			WithAlias("old", "new"),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		Expect(d.Load(mapProvider{
+			"old": map[string]any{"port": "8080", "hosts": []any{"a", "b"}, "tls": map[string]any{"on": "true"}},
+			"new": map[string]any{"port": 8080, "hosts": []string{"a", "b"}, "tls": map[string]any{"on": true}},
+		}, nil)).To(Succeed())
+		Expect(d.GetInt("new.port")).To(Equal(8080))
+		Expect(d.GetStringSlice("new.hosts")).To(Equal([]string{"a", "b"}))
+		Expect(d.GetBool("new.tls.on")).To(BeTrue())
+		Expect(d.lookup("new.tls")).To(Equal(map[string]any{"on": true}))
+
+		Expect(d.Set("new.hosts", []string{"a", "c"})).To(Succeed())
+		Expect(d.GetStringSlice("new.hosts")).Error().To(MatchError(
+			"conflicting configuration settings new.hosts and deprecated old.hosts"))
+		Expect(d.Set("new.port", []int{8080})).To(Succeed())
+		Expect(d.GetString("new.port")).Error().To(HaveOccurred())
+	})
+
+})
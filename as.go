@@ -25,13 +25,13 @@ import (
 // as looks up the value for the specified path, and if successful, returns the
 // value as of type T and using pflag conversion rules. If the value does not
 // exist or cannot be converted into a value of type T, an error is returned
-// instead.
+// instead; conversion errors are reported as [*ConversionError].
 func as[T any](d *DeafAdder, path string, fn func(*pflag.FlagSet, string, T, string) *T, treatAsScalar bool) (v T, err error) {
 	// Let's see if we can get a configValue for the specified element; if not, we're
 	// done, nothing we can do about it.
-	configValue := d.Get(path)
-	if configValue == nil {
-		return v, fmt.Errorf("no such configuration setting %s", path)
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return v, err
 	}
 	// We got a value, so let's now create/construct a suitable throw-away flag
 	// as part of a throw-away flag set. Notice how creating the flag will give
@@ -50,35 +50,141 @@ func as[T any](d *DeafAdder, path string, fn func(*pflag.FlagSet, string, T, str
 	// logic. Here, we need to differentiate between scalar and slice flag
 	// values. Note that scalar flags can have struct types, such as net.IP.
 	flag := fs.Lookup(flagName)
-	if !treatAsScalar && flagValueT.Kind() == reflect.Slice {
-		if reflect.TypeOf(configValue).Kind() != reflect.Slice {
-			return v, fmt.Errorf("value for configuration setting %s must be slice", path)
-		}
-		if fsv, ok := flag.Value.(pflag.SliceValue); ok {
-			cr := reflect.ValueOf(configValue)
-			cl := make([]string, cr.Len())
-			for idx := range cl {
-				cl[idx] = fmt.Sprintf("%v", cr.Index(idx))
-			}
-			if err := fsv.Replace(cl); err != nil {
-				return v, err
-			}
-			return *pFlagValue, nil
-		}
-		cr := reflect.ValueOf(configValue)
-		sl := make([]string, cr.Len())
-		for idx := range sl {
-			sl[idx] = fmt.Sprintf("%v", cr.Index(idx))
+	isSlice := !treatAsScalar && flagValueT.Kind() == reflect.Slice
+	if isSlice && reflect.TypeOf(configValue).Kind() != reflect.Slice {
+		return v, fmt.Errorf("value for configuration setting %s must be slice", d.abs(path))
+	}
+	if err := setValue(flag.Value, configValue, isSlice); err != nil {
+		return v, d.redact(path, &ConversionError{Path: d.abs(path), Value: configValue, Err: err})
+	}
+	return *pFlagValue, nil
+}
+
+// setValue sets the specified flag value from the specified configuration
+// value. If isSlice is true, then the configuration value must be a slice and
+// its elements are either passed to the flag value's Replace method if it is a
+// [pflag.SliceValue], or otherwise are passed as a comma-separated list to the
+// flag value's Set method. In case isSlice is false, the textual
+// representation of the configuration value is passed to Set.
+func setValue(value pflag.Value, configValue any, isSlice bool) error {
+	if !isSlice {
+		return value.Set(fmt.Sprintf("%v", configValue))
+	}
+	cr := reflect.ValueOf(configValue)
+	sl := make([]string, cr.Len())
+	for idx := range sl {
+		sl[idx] = fmt.Sprintf("%v", cr.Index(idx))
+	}
+	if fsv, ok := value.(pflag.SliceValue); ok {
+		return fsv.Replace(sl)
+	}
+	return value.Set(strings.Join(sl, ","))
+}
+
+// parse looks up the value for the specified path, and if successful, returns
+// the value as of type T using the specified parse function on the textual
+// representation of the value. If the value does not exist or cannot be
+// parsed, an error is returned instead; parse errors are reported as
+// [*ConversionError].
+func parse[T any](d *DeafAdder, path string, fn func(string) (T, error)) (v T, err error) {
+	return convert(d, path, textual(fn))
+}
+
+// parseSlice looks up the slice value for the specified path, and if
+// successful, returns the slice elements as values of type T using the
+// specified parse function, similar to [parse]. If the value does not exist,
+// isn't a slice, or any of its elements cannot be parsed, an error is returned
+// instead.
+func parseSlice[T any](d *DeafAdder, path string, fn func(string) (T, error)) (v []T, err error) {
+	return convertSlice(d, path, textual(fn))
+}
+
+// convert looks up the value for the specified path, and if successful,
+// returns the value as of type T using the specified conversion function. In
+// contrast to [parse], the conversion function gets passed the value as
+// returned by the configuration parser, such as a time.Time value.
+func convert[T any](d *DeafAdder, path string, fn func(any) (T, error)) (v T, err error) {
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return v, err
+	}
+	v, err = fn(configValue)
+	if err != nil {
+		return v, d.redact(path, &ConversionError{Path: d.abs(path), Value: configValue, Err: err})
+	}
+	return v, nil
+}
+
+// convertSlice looks up the slice value for the specified path, and if
+// successful, returns the slice elements as values of type T using the
+// specified conversion function, similar to [convert].
+func convertSlice[T any](d *DeafAdder, path string, fn func(any) (T, error)) (v []T, err error) {
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return v, err
+	}
+	cr := reflect.ValueOf(configValue)
+	if cr.Kind() != reflect.Slice {
+		return v, fmt.Errorf("value for configuration setting %s must be slice", d.abs(path))
+	}
+	v = make([]T, cr.Len())
+	for idx := range v {
+		element := cr.Index(idx).Interface()
+		ev, err := fn(element)
+		if err != nil {
+			return nil, d.redact(path, &ConversionError{
+				Path:  fmt.Sprintf("%s[%d]", d.abs(path), idx),
+				Value: element,
+				Err:   err,
+			})
 		}
-		if err := flag.Value.Set(strings.Join(sl, ",")); err != nil {
-			return v, err
+		v[idx] = ev
+	}
+	return v, nil
+}
+
+// textual returns a conversion function that passes the textual representation
+// of a value to the specified parse function.
+func textual[T any](fn func(string) (T, error)) func(any) (T, error) {
+	return func(value any) (T, error) {
+		return fn(fmt.Sprintf("%v", value))
+	}
+}
+
+// typedOrTextual returns a conversion function that returns values already of
+// type T as-is, and otherwise passes the textual representation of a value to
+// the specified parse function.
+func typedOrTextual[T any](fn func(string) (T, error)) func(any) (T, error) {
+	return func(value any) (T, error) {
+		if v, ok := value.(T); ok {
+			return v, nil
 		}
-		return *pFlagValue, nil
+		return fn(fmt.Sprintf("%v", value))
 	}
-	if err := flag.Value.Set(fmt.Sprintf("%v", configValue)); err != nil {
-		return v, err
+}
+
+// lookup returns the value for the specified path, taking deprecated aliases
+// into account and interpolated if enabled, or an error if there is no such
+// value. For views, lookup delegates to the top-level DeafAdder object.
+func (d *DeafAdder) lookup(path string) (any, error) {
+	if d.root != nil {
+		return d.root.lookup(d.abs(path))
 	}
-	return *pFlagValue, nil
+	configValue, err := d.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	if configValue == nil {
+		return nil, fmt.Errorf("no such configuration setting %s", path)
+	}
+	if d.settings().interpolate {
+		value, err := d.interpolate(configValue, []string{path})
+		if err != nil {
+			return nil, d.redact(path, &ConversionError{Path: path, Value: configValue, Err: err})
+		}
+		return value, nil
+	}
+	return configValue, nil
 }
 
 const flagName = "flag"
@@ -0,0 +1,243 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// ByteSize is a number of bytes, such as a cache size or buffer limit. It
+// implements [pflag.Value], so that CLI flags and configuration settings accept
+// exactly the same textual representations; see [ParseByteSize] for details.
+type ByteSize uint64
+
+// The SI and IEC byte size units.
+const (
+	B ByteSize = 1
+
+	KB ByteSize = 1000 * B
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+	EB ByteSize = 1000 * PB
+
+	KiB ByteSize = 1024 * B
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+	EiB ByteSize = 1024 * PiB
+)
+
+type byteSizeUnit struct {
+	suffix string
+	size   ByteSize
+}
+
+// byteSizeUnits lists the suffixes ParseByteSize understands, longer suffixes
+// first so that “KiB” doesn't get mistaken for “B”. Please note that there
+// deliberately is no single-letter “E” suffix, as it would clash with
+// exponents.
+var byteSizeUnits = []byteSizeUnit{
+	{"KiB", KiB}, {"MiB", MiB}, {"GiB", GiB}, {"TiB", TiB}, {"PiB", PiB}, {"EiB", EiB},
+	{"KB", KB}, {"MB", MB}, {"GB", GB}, {"TB", TB}, {"PB", PB}, {"EB", EB},
+	{"K", KB}, {"M", MB}, {"G", GB}, {"T", TB}, {"P", PB},
+	{"B", B},
+}
+
+// canonicalByteSizeUnits lists the units String renders, in order of
+// preference, that is, in descending size.
+var canonicalByteSizeUnits = []byteSizeUnit{
+	{"EiB", EiB}, {"EB", EB}, {"PiB", PiB}, {"PB", PB}, {"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"KB", KB},
+}
+
+var byteSizeNumberRe = regexp.MustCompile(`^(\d+(\.\d*)?|\.\d+)([eE][+-]?\d{1,3})?$`)
+
+var maxByteSize = new(big.Int).SetUint64(math.MaxUint64)
+
+// ParseByteSize parses the textual representation of a byte size, consisting of
+// a non-negative, optionally fractional number followed by an optional SI or
+// IEC unit suffix, such as “512MiB”, “1.5GB”, or just “4096”. Unit suffixes
+// are case-insensitive and the single-letter suffixes K, M, G, T, and P are SI
+// units. Fractional numbers of bytes are truncated.
+func ParseByteSize(s string) (ByteSize, error) {
+	text := strings.TrimSpace(s)
+	size := B
+	for _, unit := range byteSizeUnits {
+		if len(text) >= len(unit.suffix) &&
+			strings.EqualFold(text[len(text)-len(unit.suffix):], unit.suffix) {
+			text = strings.TrimSpace(text[:len(text)-len(unit.suffix)])
+			size = unit.size
+			break
+		}
+	}
+	if !byteSizeNumberRe.MatchString(text) {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	number, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	number.Mul(number, new(big.Rat).SetUint64(uint64(size)))
+	bytes := new(big.Int).Quo(number.Num(), number.Denom())
+	if bytes.Cmp(maxByteSize) > 0 {
+		return 0, fmt.Errorf("byte size %q out of range", s)
+	}
+	return ByteSize(bytes.Uint64()), nil
+}
+
+// String returns the canonical textual representation of the byte size, using
+// the largest SI or IEC unit that represents the size exactly, and otherwise
+// just bytes.
+func (b ByteSize) String() string {
+	if b != 0 {
+		for _, unit := range canonicalByteSizeUnits {
+			if b%unit.size == 0 {
+				return fmt.Sprintf("%d%s", b/unit.size, unit.suffix)
+			}
+		}
+	}
+	return fmt.Sprintf("%dB", uint64(b))
+}
+
+// Set sets the byte size from its textual representation.
+func (b *ByteSize) Set(s string) error {
+	v, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// Type returns the name of the byte size flag value type.
+func (b *ByteSize) Type() string {
+	return "byteSize"
+}
+
+// ByteSizeVar defines a byte size flag with specified name, default value, and
+// usage string. The argument p points to a ByteSize variable in which to store
+// the value of the flag.
+func ByteSizeVar(fs *pflag.FlagSet, p *ByteSize, name string, value ByteSize, usage string) {
+	*p = value
+	fs.Var(p, name, usage)
+}
+
+// ByteSizeSliceVar defines a byte size slice flag with specified name, default
+// value, and usage string. The argument p points to a []ByteSize variable in
+// which to store the value of the flag.
+func ByteSizeSliceVar(fs *pflag.FlagSet, p *[]ByteSize, name string, value []ByteSize, usage string) {
+	*p = value
+	fs.Var(&byteSizeSliceValue{value: p}, name, usage)
+}
+
+func byteSizeConstructor(fs *pflag.FlagSet, name string, value ByteSize, usage string) *ByteSize {
+	p := new(ByteSize)
+	ByteSizeVar(fs, p, name, value, usage)
+	return p
+}
+
+func byteSizeSliceConstructor(fs *pflag.FlagSet, name string, value []ByteSize, usage string) *[]ByteSize {
+	p := new([]ByteSize)
+	ByteSizeSliceVar(fs, p, name, value, usage)
+	return p
+}
+
+// byteSizeSliceValue implements [pflag.Value] and [pflag.SliceValue] for byte
+// size slices, following the usual pflag slice semantics: the first Set
+// replaces the default value, subsequent Sets append.
+type byteSizeSliceValue struct {
+	value   *[]ByteSize
+	changed bool
+}
+
+var (
+	_ pflag.Value      = (*byteSizeSliceValue)(nil)
+	_ pflag.SliceValue = (*byteSizeSliceValue)(nil)
+)
+
+func (s *byteSizeSliceValue) Set(val string) error {
+	out, err := parseByteSizes(strings.Split(val, ","))
+	if err != nil {
+		return err
+	}
+	if !s.changed {
+		*s.value = out
+	} else {
+		*s.value = append(*s.value, out...)
+	}
+	s.changed = true
+	return nil
+}
+
+func (s *byteSizeSliceValue) Type() string {
+	return "byteSizeSlice"
+}
+
+func (s *byteSizeSliceValue) String() string {
+	return "[" + strings.Join(s.GetSlice(), ",") + "]"
+}
+
+func (s *byteSizeSliceValue) Append(val string) error {
+	v, err := ParseByteSize(val)
+	if err != nil {
+		return err
+	}
+	*s.value = append(*s.value, v)
+	return nil
+}
+
+func (s *byteSizeSliceValue) Replace(val []string) error {
+	out, err := parseByteSizes(val)
+	if err != nil {
+		return err
+	}
+	*s.value = out
+	return nil
+}
+
+func (s *byteSizeSliceValue) GetSlice() []string {
+	out := make([]string, len(*s.value))
+	for idx, v := range *s.value {
+		out[idx] = v.String()
+	}
+	return out
+}
+
+func parseByteSizes(vals []string) ([]ByteSize, error) {
+	out := make([]ByteSize, len(vals))
+	var errs []error
+	for idx, val := range vals {
+		v, err := ParseByteSize(val)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out[idx] = v
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"math"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("byte sizes", func() {
+
+	DescribeTable("parsing",
+		func(s string, expected ByteSize) {
+			Expect(ParseByteSize(s)).To(Equal(expected))
+		},
+		Entry(nil, "0", ByteSize(0)),
+		Entry(nil, "4096", ByteSize(4096)),
+		Entry(nil, "42B", ByteSize(42)),
+		Entry(nil, " 512 MiB ", 512*MiB),
+		Entry(nil, "512mib", 512*MiB),
+		Entry(nil, "1.5GB", 1500*MB),
+		Entry(nil, "1.5KiB", ByteSize(1536)),
+		Entry(nil, "1.1KiB", ByteSize(1126)),
+		Entry(nil, "2k", 2*KB),
+		Entry(nil, "1.5e+09", 1500*MB),
+		Entry(nil, "1e3KB", MB),
+		Entry(nil, "15EiB", 15*EiB),
+		Entry(nil, "18446744073709551615", ByteSize(math.MaxUint64)),
+	)
+
+	DescribeTable("rejecting invalid byte sizes",
+		func(s string) {
+			Expect(ParseByteSize(s)).Error().To(HaveOccurred())
+		},
+		Entry(nil, ""),
+		Entry(nil, "MiB"),
+		Entry(nil, "-1"),
+		Entry(nil, "1/2"),
+		Entry(nil, "0x10"),
+		Entry(nil, "1e"),
+		Entry(nil, "1e9999"),
+		Entry(nil, "42 parsecs"),
+		Entry(nil, "16EiB"),
+		Entry(nil, "18446744073709551616"),
+	)
+
+	DescribeTable("canonical string form",
+		func(b ByteSize, expected string) {
+			Expect(b.String()).To(Equal(expected))
+			Expect(ParseByteSize(expected)).To(Equal(b))
+		},
+		Entry(nil, ByteSize(0), "0B"),
+		Entry(nil, ByteSize(42), "42B"),
+		Entry(nil, ByteSize(1536), "1536B"),
+		Entry(nil, 3*KiB, "3KiB"),
+		Entry(nil, 1000*KiB, "1000KiB"),
+		Entry(nil, 512*MiB, "512MiB"),
+		Entry(nil, 1500*MB, "1500MB"),
+		Entry(nil, 2*GB, "2GB"),
+		Entry(nil, ByteSize(math.MaxUint64), "18446744073709551615B"),
+	)
+
+	It("works as a flag value", func() {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		var size ByteSize
+		var sizes []ByteSize
+		ByteSizeVar(fs, &size, "size", 1*KiB, "size")
+		ByteSizeSliceVar(fs, &sizes, "sizes", []ByteSize{1}, "sizes")
+		Expect(size).To(Equal(KiB))
+		Expect(fs.Parse([]string{"--size=2MiB", "--sizes=1KB,2KB", "--sizes=3KB"})).To(Succeed())
+		Expect(size).To(Equal(2 * MiB))
+		Expect(sizes).To(Equal([]ByteSize{KB, 2 * KB, 3 * KB}))
+		Expect(fs.Lookup("sizes").Value.String()).To(Equal("[1KB,2KB,3KB]"))
+		Expect(fs.Parse([]string{"--sizes=1KB,foo"})).NotTo(Succeed())
+	})
+
+	It("retrieves byte sizes from a configuration", func() {
+		d := load(`
+cache: 512MiB
+buffer: 4096
+limits:
+  - 1.5GB
+  - 1KiB
+bad-limits:
+  - 1KiB
+  - 1 parsec
+`)
+		Expect(d.GetByteSize("cache")).To(Equal(512 * MiB))
+		Expect(d.GetByteSize("buffer")).To(Equal(ByteSize(4096)))
+		Expect(d.GetByteSizeSlice("limits")).To(Equal([]ByteSize{1500 * MB, KiB}))
+		Expect(d.GetByteSizeSlice("bad-limits")).Error().To(
+			MatchError(ContainSubstring(`invalid byte size "1 parsec"`)))
+	})
+
+})
@@ -16,6 +16,7 @@ package deafadder
 
 import (
 	"net"
+	"sync"
 	"time"
 
 	"github.com/knadh/koanf/v2"
@@ -29,13 +30,26 @@ import (
 // [pflag]: https://github.com/spf13/pflag
 type DeafAdder struct {
 	*koanf.Koanf
+	opts *options
+	once sync.Once // lazily creating opts, see settings
+
+	indexMu sync.Mutex
+	index   map[string][]string // keys by normalized key, see normalizedKeys
+
+	root   *DeafAdder // top-level DeafAdder object of a view, see Sub
+	prefix string     // absolute path of a view's subtree
 }
 
 // New returns a new DeafAdder object, wrapping the passed koanf.Koanf
-// configuration data object.
-func New(k *koanf.Koanf) *DeafAdder {
+// configuration data object and configured using the optionally specified
+// options.
+func New(k *koanf.Koanf, opts ...Option) *DeafAdder {
 	d := &DeafAdder{
-		k,
+		Koanf: k,
+		opts:  &options{},
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
 	return d
 }
@@ -58,6 +72,18 @@ func (d *DeafAdder) GetBytesHex(path string) (v []byte, err error) {
 	return as(d, path, (*pflag.FlagSet).BytesHex, true)
 }
 
+// GetByteSize returns the ByteSize value of a configuration setting with the
+// given name.
+func (d *DeafAdder) GetByteSize(path string) (v ByteSize, err error) {
+	return as(d, path, byteSizeConstructor, false)
+}
+
+// GetByteSizeSlice returns the []ByteSize value of a configuration setting with
+// the given name.
+func (d *DeafAdder) GetByteSizeSlice(path string) (v []ByteSize, err error) {
+	return as(d, path, byteSizeSliceConstructor, false)
+}
+
 // GetCount returns the int value of a configuration setting with the given
 // name.
 func (d *DeafAdder) GetCount(path string) (v int, err error) {
@@ -154,24 +180,45 @@ func (d *DeafAdder) GetInt64Slice(path string) (v []int64, err error) {
 // GetIP returns the net.IP value of a configuration setting with the given
 // name.
 func (d *DeafAdder) GetIP(path string) (v net.IP, err error) {
+	if d.settings().strictIP {
+		return parse(d, path, parseStrictIP)
+	}
 	return as(d, path, (*pflag.FlagSet).IP, true)
 }
 
 // GetIPSlice returns the []net.IP value of a configuration setting with the
 // given name.
 func (d *DeafAdder) GetIPSlice(path string) (v []net.IP, err error) {
+	if d.settings().strictIP {
+		return parseSlice(d, path, parseStrictIP)
+	}
 	return as(d, path, (*pflag.FlagSet).IPSlice, false)
 }
 
+// GetIPMask returns the net.IPMask value of a configuration setting with the
+// given name.
+func (d *DeafAdder) GetIPMask(path string) (v net.IPMask, err error) {
+	if d.settings().strictIP {
+		return parse(d, path, parseStrictIPMask)
+	}
+	return as(d, path, (*pflag.FlagSet).IPMask, true)
+}
+
 // GetIPNet returns the net.IPNet value of a configuration setting with the
 // given name.
 func (d *DeafAdder) GetIPNet(path string) (v net.IPNet, err error) {
+	if d.settings().strictIP {
+		return parse(d, path, parseStrictIPNet)
+	}
 	return as(d, path, (*pflag.FlagSet).IPNet, false)
 }
 
 // GetIPNetSlice returns the []net.IPNet value of a configuration setting with
 // the given name.
 func (d *DeafAdder) GetIPNetSlice(path string) (v []net.IPNet, err error) {
+	if d.settings().strictIP {
+		return parseSlice(d, path, parseStrictIPNet)
+	}
 	return as(d, path, (*pflag.FlagSet).IPNetSlice, false)
 }
 
@@ -202,4 +202,13 @@ config:
 		Expect(d.GetUint64("config.uint64")).To(Equal(uint64(45)))
 	})
 
+	It("lazily creates default settings only once", func() {
+		d := &DeafAdder{Koanf: koanf.New(".")}
+		settings := make(chan *options)
+		for range 2 {
+			go func() { settings <- d.settings() }()
+		}
+		Expect(<-settings).To(BeIdenticalTo(<-settings))
+	})
+
 })
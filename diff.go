@@ -0,0 +1,123 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Getter returns the typed value of the configuration setting with the given
+// name.
+type Getter func(d *DeafAdder, path string) (any, error)
+
+// Typed returns a [Getter] for the specified typed accessor, such as
+// (*DeafAdder).GetDuration.
+func Typed[T any](get func(*DeafAdder, string) (T, error)) Getter {
+	return func(d *DeafAdder, path string) (any, error) {
+		return get(d, path)
+	}
+}
+
+// Hints maps configuration setting names to the [Getter] to use in order to
+// convert the values of these settings.
+type Hints map[string]Getter
+
+// Change describes an added, removed, or changed configuration setting. Old is
+// nil for added settings, and New is nil for removed settings.
+type Change struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// Differences lists the added, removed, and changed configuration settings,
+// each sorted by their names.
+type Differences struct {
+	Added   []Change
+	Removed []Change
+	Changed []Change
+}
+
+// Empty returns true if there are no differences at all.
+func (d Differences) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the configuration settings of from and to, returning the
+// added, removed, and changed settings. Settings with a [Getter] in the
+// specified hints get compared after conversion, so that, for instance, “10s”
+// and “10000ms” are considered to be the same duration. All other settings are
+// compared using their raw values.
+//
+// If a setting cannot be converted, Diff falls back to comparing its raw
+// values and additionally reports the conversion error; all conversion errors
+// are joined.
+func Diff(from, to *DeafAdder, hints Hints) (Differences, error) {
+	var diffs Differences
+	var errs []error
+	fromk, tok := from.current(), to.current()
+	value := func(d *DeafAdder, path string) any {
+		if get, ok := hints[path]; ok {
+			v, err := get(d, path)
+			if err == nil {
+				return v
+			}
+			errs = append(errs, err)
+		}
+		if d == from {
+			return fromk.Get(path)
+		}
+		return tok.Get(path)
+	}
+
+	fromKeys, toKeys := fromk.Keys(), tok.Keys()
+	fromSet, toSet := keySet(fromKeys), keySet(toKeys)
+	for _, path := range fromKeys {
+		if _, ok := toSet[path]; !ok {
+			diffs.Removed = append(diffs.Removed, Change{Path: path, Old: value(from, path)})
+			continue
+		}
+		oldv := value(from, path)
+		newv := value(to, path)
+		if !reflect.DeepEqual(oldv, newv) {
+			diffs.Changed = append(diffs.Changed, Change{Path: path, Old: oldv, New: newv})
+		}
+	}
+	for _, path := range toKeys {
+		if _, ok := fromSet[path]; ok {
+			continue
+		}
+		diffs.Added = append(diffs.Added, Change{Path: path, New: value(to, path)})
+	}
+	byPath := func(a, b Change) int {
+		return strings.Compare(a.Path, b.Path)
+	}
+	slices.SortFunc(diffs.Added, byPath)
+	slices.SortFunc(diffs.Removed, byPath)
+	slices.SortFunc(diffs.Changed, byPath)
+	return diffs, errors.Join(errs...)
+}
+
+// keySet returns the set of the specified keys.
+func keySet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"net"
+	"time"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func load(s string) *DeafAdder {
+	GinkgoHelper()
+	d := New(koanf.New("."))
+	Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+	return d
+}
+
+var _ = Describe("diffing configurations", func() {
+
+	It("finds no differences in equal configurations", func() {
+		s := `
+foo: bar
+`
+		Expect(Diff(load(s), load(s), nil)).To(HaveField("Empty()", BeTrue()))
+	})
+
+	It("reports added, removed and changed settings", func() {
+		diffs, err := Diff(load(`
+timeout: 10s
+addr: 10.0.0.1
+gone: 42
+answer: 42
+`), load(`
+timeout: 10000ms
+addr: "::ffff:10.0.0.1"
+answer: 666
+new: baz
+`), Hints{
+			"timeout": Typed((*DeafAdder).GetDuration),
+			"addr":    Typed((*DeafAdder).GetIP),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs.Added).To(ConsistOf(Change{Path: "new", New: "baz"}))
+		Expect(diffs.Removed).To(ConsistOf(Change{Path: "gone", Old: 42}))
+		Expect(diffs.Changed).To(ConsistOf(Change{Path: "answer", Old: 42, New: 666}))
+	})
+
+	It("compares typed values", func() {
+		diffs, err := Diff(load(`
+timeout: 10s
+addr: 10.0.0.1
+`), load(`
+timeout: 1m
+addr: 10.0.0.2
+`), Hints{
+			"timeout": Typed((*DeafAdder).GetDuration),
+			"addr":    Typed((*DeafAdder).GetIP),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diffs.Changed).To(ConsistOf(
+			Change{Path: "addr", Old: net.ParseIP("10.0.0.1"), New: net.ParseIP("10.0.0.2")},
+			Change{Path: "timeout", Old: 10 * time.Second, New: time.Minute},
+		))
+	})
+
+	It("falls back to raw values on conversion errors", func() {
+		diffs, err := Diff(load(`
+timeout: 10s
+`), load(`
+timeout: 10 light years
+`), Hints{
+			"timeout": Typed((*DeafAdder).GetDuration),
+		})
+		Expect(err).To(MatchError(ContainSubstring("light years")))
+		Expect(diffs.Changed).To(ConsistOf(
+			Change{Path: "timeout", Old: 10 * time.Second, New: "10 light years"}))
+	})
+
+})
@@ -34,8 +34,10 @@ The name “deafadder” (“anguis fragilis sensu stricto”, better known as
 
 Under its hood (or rather, skin) this package leverages the conversion functions
 implemented in the [pflag] package, inheriting its behavior but also some
-quirks. For instance, [DeafAdder.GetIP] currently does not report any errors in
-case of invalid textual IP addresses.
+quirks. For instance, [DeafAdder.GetIP] does not report any errors in case of
+empty textual IP addresses, and [DeafAdder.GetIPNet] silently drops host bits.
+Use the [WithStrictIP] option to report such values as [*ConversionError]
+instead, or use the net/netip-based accessors, such as [DeafAdder.GetAddr].
 
 [pflag]: https://github.com/spf13/pflag
 [cobra]: https://github.com/spf13/cobra
@@ -0,0 +1,285 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DumpFormat specifies the output format of [DeafAdder.Dump].
+type DumpFormat int
+
+const (
+	DumpYAML  DumpFormat = iota // nested YAML, annotations as line comments
+	DumpJSON                    // nested JSON, annotated leaves as objects
+	DumpTable                   // flat “path = value” lines
+)
+
+// DumpOption configures [DeafAdder.Dump].
+type DumpOption func(*dumpOptions)
+
+type dumpOptions struct {
+	types   bool
+	hints   Hints
+	origins bool
+}
+
+// DumpTypes annotates the dumped configuration settings with their types.
+// Settings with a [Getter] in the specified hints are annotated with the type
+// they get converted to, such as time.Duration; all other settings are
+// annotated with the types of their raw values.
+func DumpTypes(hints Hints) DumpOption {
+	return func(o *dumpOptions) {
+		o.types = true
+		o.hints = hints
+	}
+}
+
+// DumpOrigins annotates the dumped configuration settings with the names of
+// the configuration files that supplied them, see [DeafAdder.Origin].
+func DumpOrigins() DumpOption {
+	return func(o *dumpOptions) {
+		o.origins = true
+	}
+}
+
+// dumpEntry is a single configuration setting to be dumped.
+type dumpEntry struct {
+	path   string
+	value  any
+	typ    string
+	origin string
+}
+
+// annotation returns the type and origin annotation of this entry, or "".
+func (e dumpEntry) annotation() string {
+	var annotations []string
+	for _, annotation := range []string{e.typ, e.origin} {
+		if annotation != "" {
+			annotations = append(annotations, annotation)
+		}
+	}
+	return strings.Join(annotations, ", ")
+}
+
+// Dump writes the effective configuration settings in the specified format to
+// w, sorted by their names. The values of configuration settings marked as
+// secret, see [DeafAdder.IsSecret], are redacted, and so are lists containing
+// secret elements and values interpolating secrets. Optionally, the dumped
+// settings can be annotated with their types and origins, see [DumpTypes] and
+// [DumpOrigins].
+//
+// If the values of some settings cannot be looked up or converted, Dump falls
+// back to their raw values and raw types, and reports the errors after writing
+// the dump; all such errors are joined.
+func (d *DeafAdder) Dump(w io.Writer, format DumpFormat, opts ...DumpOption) error {
+	var o dumpOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var errs []error
+	var entries []dumpEntry
+	k := d.current()
+	for _, key := range k.Keys() {
+		entry := dumpEntry{path: key}
+		value, err := d.lookup(key)
+		if err != nil {
+			errs = append(errs, err)
+			value = k.Get(key)
+		}
+		if o.types {
+			typ := reflect.TypeOf(value)
+			if get, ok := o.hints[key]; ok {
+				v, err := get(d, key)
+				if err != nil {
+					errs = append(errs, err)
+				} else {
+					typ = reflect.TypeOf(v)
+				}
+			}
+			if typ != nil {
+				entry.typ = typ.String()
+			}
+		}
+		if d.top().secretive(d.abs(key), map[string]bool{}) {
+			value = Redacted
+		}
+		if o.origins {
+			entry.origin = d.Origin(key)
+		}
+		entry.value = value
+		entries = append(entries, entry)
+	}
+	var err error
+	switch format {
+	case DumpYAML:
+		err = d.dumpYAML(w, entries)
+	case DumpJSON:
+		err = d.dumpJSON(w, entries)
+	case DumpTable:
+		err = dumpTable(w, entries)
+	default:
+		err = fmt.Errorf("unknown dump format %d", format)
+	}
+	return errors.Join(append([]error{err}, errs...)...)
+}
+
+// dumpYAML writes the specified entries as nested YAML, with annotations as
+// line comments.
+func (d *DeafAdder) dumpYAML(w io.Writer, entries []dumpEntry) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range entries {
+		node := root
+		segments := strings.Split(entry.path, d.Delim())
+		for _, segment := range segments[:len(segments)-1] {
+			node = yamlChild(node, segment)
+		}
+		value := &yaml.Node{}
+		if err := value.Encode(entry.value); err != nil {
+			return err
+		}
+		value.LineComment = entry.annotation()
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: segments[len(segments)-1]},
+			value)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// yamlChild returns the child mapping node with the specified key, appending
+// it if necessary. As entries are sorted, an existing child node can only be
+// the last one.
+func yamlChild(node *yaml.Node, key string) *yaml.Node {
+	if n := len(node.Content); n > 0 && node.Content[n-2].Value == key &&
+		node.Content[n-1].Kind == yaml.MappingNode {
+		return node.Content[n-1]
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		child)
+	return child
+}
+
+// dumpJSON writes the specified entries as nested JSON. Annotated entries are
+// written as objects with “value”, “type”, and “origin” fields.
+func (d *DeafAdder) dumpJSON(w io.Writer, entries []dumpEntry) error {
+	root := map[string]any{}
+	for _, entry := range entries {
+		node := root
+		segments := strings.Split(entry.path, d.Delim())
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[segment] = child
+			}
+			node = child
+		}
+		var value any = entry.value
+		if entry.typ != "" || entry.origin != "" {
+			annotated := map[string]any{"value": entry.value}
+			if entry.typ != "" {
+				annotated["type"] = entry.typ
+			}
+			if entry.origin != "" {
+				annotated["origin"] = entry.origin
+			}
+			value = annotated
+		}
+		node[segments[len(segments)-1]] = value
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// dumpTable writes the specified entries as flat “path = value” lines, with
+// values in JSON notation and annotations as trailing comments.
+func dumpTable(w io.Writer, entries []dumpEntry) error {
+	for _, entry := range entries {
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return err
+		}
+		line := entry.path + " = " + string(value)
+		if annotation := entry.annotation(); annotation != "" {
+			line += " # " + annotation
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretive returns true if the configuration setting with the specified
+// absolute path is a secret, contains secret list elements, such as
+// “listeners[0].password”, or interpolates secrets. Paths and secrets are
+// compared by their canonical names, so that secrets set under deprecated
+// aliases or unnormalized keys get detected, too.
+func (d *DeafAdder) secretive(path string, seen map[string]bool) bool {
+	path = d.canonical(path)
+	if seen[path] {
+		return false
+	}
+	seen[path] = true
+	o := d.settings()
+	o.mu.RLock()
+	for secret := range o.secrets {
+		secret = d.canonical(secret)
+		if secret == path || strings.HasPrefix(secret, path+"[") {
+			o.mu.RUnlock()
+			return true
+		}
+	}
+	o.mu.RUnlock()
+	if !o.interpolate {
+		return false
+	}
+	value, _ := d.raw(path)
+	var texts []any
+	switch v := value.(type) {
+	case string:
+		texts = []any{v}
+	case []any:
+		texts = v
+	}
+	for _, text := range texts {
+		s, ok := text.(string)
+		if !ok {
+			continue
+		}
+		for _, ref := range references(s) {
+			if d.secretive(ref, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"strings"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dumping configurations", func() {
+
+	const conf = `
+server:
+  timeout: 10s
+  port: 8080
+  password: hunter2
+name: deafadder
+tags: [a, b]
+`
+
+	var d *DeafAdder
+	var name string
+
+	BeforeEach(func() {
+		name = writeFile(GinkgoT().TempDir(), "conf.yaml", conf)
+		d = New(koanf.New("."), WithSecrets("server.password"))
+		Expect(d.LoadFile(name, yaml.Parser())).To(Succeed())
+	})
+
+	dump := func(format DumpFormat, opts ...DumpOption) string {
+		GinkgoHelper()
+		var out strings.Builder
+		Expect(d.Dump(&out, format, opts...)).To(Succeed())
+		return out.String()
+	}
+
+	It("dumps as sorted and redacted YAML", func() {
+		Expect(dump(DumpYAML)).To(Equal(`name: deafadder
+server:
+  password: '[REDACTED]'
+  port: 8080
+  timeout: 10s
+tags:
+  - a
+  - b
+`))
+	})
+
+	It("dumps annotated YAML", func() {
+		hints := Hints{"server.timeout": Typed((*DeafAdder).GetDuration)}
+		out := dump(DumpYAML, DumpTypes(hints), DumpOrigins())
+		Expect(out).To(ContainSubstring("  port: 8080 # int, " + name + "\n"))
+		Expect(out).To(ContainSubstring("  timeout: 10s # time.Duration, " + name + "\n"))
+	})
+
+	It("dumps as JSON", func() {
+		Expect(dump(DumpJSON)).To(MatchJSON(`{
+  "name": "deafadder",
+  "server": {"password": "[REDACTED]", "port": 8080, "timeout": "10s"},
+  "tags": ["a", "b"]
+}`))
+		Expect(dump(DumpJSON, DumpTypes(nil))).To(ContainSubstring(
+			`"port": {
+      "type": "int",
+      "value": 8080
+    }`))
+	})
+
+	It("dumps as flat table", func() {
+		Expect(dump(DumpTable)).To(Equal(`name = "deafadder"
+server.password = "[REDACTED]"
+server.port = 8080
+server.timeout = "10s"
+tags = ["a","b"]
+`))
+		Expect(dump(DumpTable, DumpOrigins())).To(ContainSubstring(
+			`server.port = 8080 # ` + name + "\n"))
+	})
+
+	It("redacts interpolated secrets and secrets inside lists", func() {
+		d = New(koanf.New("."),
+			WithInterpolation(),
+			WithSecrets("db.password", "listeners[1].password"))
+		Expect(d.Load(mapProvider{
+			"db": map[string]any{
+				"password": "hunter2",
+				"dsn":      "postgres://u:${db.password}@h",
+				"url":      "${db.dsn}",
+				"host":     "h",
+			},
+			"listeners": []any{
+				map[string]any{"port": 80},
+				map[string]any{"port": 443, "password": "hunter3"},
+			},
+		}, nil)).To(Succeed())
+		out := dump(DumpTable)
+		Expect(out).NotTo(ContainSubstring("hunter"))
+		Expect(out).To(Equal(`db.dsn = "[REDACTED]"
+db.host = "h"
+db.password = "[REDACTED]"
+db.url = "[REDACTED]"
+listeners = "[REDACTED]"
+`))
+	})
+
+	It("redacts secrets under deprecated aliases and unnormalized keys", func() {
+		d = New(koanf.New("."),
+			WithAlias("database.password", "db.password"),
+			WithNormalizeFunc(NormalizeDashes),
+			WithSecrets("db.password", "db.pass-word"))
+		Expect(d.Load(mapProvider{
+			"database": map[string]any{"password": "hunter2"},
+			"db":       map[string]any{"pass_word": "hunter3"},
+		}, nil)).To(Succeed())
+		out := dump(DumpTable)
+		Expect(out).NotTo(ContainSubstring("hunter"))
+		Expect(out).To(Equal(`database.password = "[REDACTED]"
+db.pass_word = "[REDACTED]"
+`))
+	})
+
+	It("reports conversion errors after dumping", func() {
+		hints := Hints{"name": Typed((*DeafAdder).GetDuration)}
+		var out strings.Builder
+		Expect(d.Dump(&out, DumpTable, DumpTypes(hints))).To(MatchError(ContainSubstring(
+			"configuration setting name")))
+		Expect(out.String()).To(ContainSubstring(`name = "deafadder" # string`))
+		Expect(d.Dump(&out, DumpFormat(42))).To(MatchError("unknown dump format 42"))
+	})
+
+})
@@ -0,0 +1,193 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Enum describes the allowed values of an enumeration (choice) setting, such as
+// “json|text|logfmt”, optionally with aliases and case-insensitive matching.
+type Enum struct {
+	Allowed  []string          // allowed values, in canonical form
+	Aliases  map[string]string // optional aliases mapping to allowed values
+	FoldCase bool              // match values and aliases case-insensitively
+}
+
+// EnumError reports a value not in the set of allowed enumeration values.
+type EnumError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid value %q, allowed values: %s",
+		e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// Parse returns the canonical allowed value for the specified textual value,
+// resolving aliases as necessary. Otherwise, it returns an [*EnumError].
+func (e Enum) Parse(s string) (string, error) {
+	equal := func(a, b string) bool { return a == b }
+	if e.FoldCase {
+		equal = strings.EqualFold
+	}
+	for _, allowed := range e.Allowed {
+		if equal(s, allowed) {
+			return allowed, nil
+		}
+	}
+	for alias, allowed := range e.Aliases {
+		if equal(s, alias) {
+			return allowed, nil
+		}
+	}
+	return "", &EnumError{Value: s, Allowed: e.Allowed}
+}
+
+// GetEnum returns the canonical enumeration value of a configuration setting
+// with the given name. The value always gets checked against the enumeration,
+// even if the configuration parser already returned a string.
+func (d *DeafAdder) GetEnum(path string, e Enum) (v string, err error) {
+	return parse(d, path, e.Parse)
+}
+
+// GetEnumSlice returns the canonical []string enumeration values of a
+// configuration setting with the given name.
+func (d *DeafAdder) GetEnumSlice(path string, e Enum) (v []string, err error) {
+	return parseSlice(d, path, e.Parse)
+}
+
+// SetEnum sets the configuration setting with the given name to the canonical
+// form of the specified enumeration value, or returns an [*EnumError] if the
+// value isn't allowed.
+func (d *DeafAdder) SetEnum(path string, v string, e Enum) error {
+	canonical, err := e.Parse(v)
+	if err != nil {
+		return err
+	}
+	return d.set(path, canonical)
+}
+
+// SetEnumSlice sets the configuration setting with the given name to the
+// canonical forms of the specified enumeration values, or returns an
+// [*EnumError] if any value isn't allowed.
+func (d *DeafAdder) SetEnumSlice(path string, v []string, e Enum) error {
+	canonical, err := (&enumSliceValue{enum: e}).parse(v)
+	if err != nil {
+		return err
+	}
+	return d.set(path, canonical)
+}
+
+// EnumVar defines an enumeration flag with specified name, default value,
+// allowed values, and usage string. The argument p points to a string variable
+// in which to store the canonical value of the flag. The flag enforces exactly
+// the same rules as [DeafAdder.GetEnum].
+func EnumVar(fs *pflag.FlagSet, p *string, name string, value string, e Enum, usage string) {
+	*p = value
+	fs.Var(&enumValue{value: p, enum: e}, name, usage)
+}
+
+// EnumSliceVar defines an enumeration slice flag with specified name, default
+// values, allowed values, and usage string. The argument p points to a []string
+// variable in which to store the canonical values of the flag. The flag
+// enforces exactly the same rules as [DeafAdder.GetEnumSlice].
+func EnumSliceVar(fs *pflag.FlagSet, p *[]string, name string, value []string, e Enum, usage string) {
+	*p = value
+	fs.Var(&enumSliceValue{value: p, enum: e}, name, usage)
+}
+
+// enumValue implements [pflag.Value] for enumeration values.
+type enumValue struct {
+	value *string
+	enum  Enum
+}
+
+func (v *enumValue) String() string { return *v.value }
+
+func (v *enumValue) Type() string { return strings.Join(v.enum.Allowed, "|") }
+
+func (v *enumValue) Set(s string) error {
+	canonical, err := v.enum.Parse(s)
+	if err != nil {
+		return err
+	}
+	*v.value = canonical
+	return nil
+}
+
+// enumSliceValue implements [pflag.Value] and [pflag.SliceValue] for
+// enumeration slice values, following the usual pflag slice semantics.
+type enumSliceValue struct {
+	value   *[]string
+	enum    Enum
+	changed bool
+}
+
+var _ pflag.SliceValue = (*enumSliceValue)(nil)
+
+func (v *enumSliceValue) String() string { return "[" + strings.Join(*v.value, ",") + "]" }
+
+func (v *enumSliceValue) Type() string { return strings.Join(v.enum.Allowed, "|") + "s" }
+
+func (v *enumSliceValue) Set(s string) error {
+	out, err := v.parse(strings.Split(s, ","))
+	if err != nil {
+		return err
+	}
+	if !v.changed {
+		*v.value = out
+	} else {
+		*v.value = append(*v.value, out...)
+	}
+	v.changed = true
+	return nil
+}
+
+func (v *enumSliceValue) Append(s string) error {
+	canonical, err := v.enum.Parse(s)
+	if err != nil {
+		return err
+	}
+	*v.value = append(*v.value, canonical)
+	return nil
+}
+
+func (v *enumSliceValue) Replace(s []string) error {
+	out, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	*v.value = out
+	return nil
+}
+
+func (v *enumSliceValue) GetSlice() []string { return *v.value }
+
+func (v *enumSliceValue) parse(vals []string) ([]string, error) {
+	out := make([]string, len(vals))
+	for idx, val := range vals {
+		canonical, err := v.enum.Parse(val)
+		if err != nil {
+			return nil, err
+		}
+		out[idx] = canonical
+	}
+	return out, nil
+}
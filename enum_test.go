@@ -0,0 +1,81 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("enumerations", func() {
+
+	formats := Enum{
+		Allowed: []string{"json", "text", "logfmt"},
+		Aliases: map[string]string{"plain": "text"},
+	}
+	folded := formats
+	folded.FoldCase = true
+
+	It("parses enumeration values", func() {
+		Expect(formats.Parse("json")).To(Equal("json"))
+		Expect(formats.Parse("plain")).To(Equal("text"))
+		Expect(formats.Parse("JSON")).Error().To(MatchError(
+			`invalid value "JSON", allowed values: json, text, logfmt`))
+		Expect(folded.Parse("JSON")).To(Equal("json"))
+		Expect(folded.Parse("Plain")).To(Equal("text"))
+	})
+
+	It("retrieves enumeration values", func() {
+		d := load(`
+format: Plain
+formats:
+  - JSON
+  - logfmt
+bad: xml
+`)
+		Expect(d.GetEnum("format", folded)).To(Equal("text"))
+		Expect(d.GetEnumSlice("formats", folded)).To(Equal([]string{"json", "logfmt"}))
+
+		_, err := d.GetEnum("format", formats)
+		Expect(err).To(MatchError(ContainSubstring(
+			`invalid value for configuration setting format: invalid value "Plain"`)))
+		var eerr *EnumError
+		Expect(errors.As(err, &eerr)).To(BeTrue())
+		Expect(eerr.Allowed).To(ConsistOf("json", "text", "logfmt"))
+
+		Expect(d.GetEnumSlice("formats", formats)).Error().To(
+			MatchError(ContainSubstring("formats[0]")))
+	})
+
+	It("enforces the same rules for flags", func() {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		var format string
+		var list []string
+		EnumVar(fs, &format, "format", "json", folded, "log format")
+		EnumSliceVar(fs, &list, "formats", nil, folded, "log formats")
+		Expect(fs.Lookup("format").Value.Type()).To(Equal("json|text|logfmt"))
+		Expect(fs.Parse([]string{"--format=PLAIN", "--formats=json,Text", "--formats=logfmt"})).To(Succeed())
+		Expect(format).To(Equal("text"))
+		Expect(list).To(Equal([]string{"json", "text", "logfmt"}))
+		Expect(fs.Parse([]string{"--format=xml"})).To(MatchError(ContainSubstring(
+			`invalid value "xml", allowed values: json, text, logfmt`)))
+		Expect(fs.Parse([]string{"--formats=json,xml"})).NotTo(Succeed())
+	})
+
+})
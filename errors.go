@@ -0,0 +1,43 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import "fmt"
+
+// ConversionError reports a configuration setting value that cannot be
+// converted into the requested type. For slice element values, Path includes
+// the element index, such as “addrs[1]”. For secrets, see
+// [DeafAdder.IsSecret], Value is redacted.
+type ConversionError struct {
+	Path  string // name of the configuration setting
+	Value any    // the offending value
+	Err   error  // the underlying conversion error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("invalid value for configuration setting %s: %s", e.Path, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error { return e.Err }
+
+// redact redacts the offending value of the specified conversion error if the
+// configuration setting with the given name is a secret or interpolates
+// secrets, and returns the conversion error.
+func (d *DeafAdder) redact(path string, err *ConversionError) *ConversionError {
+	if d.top().secretive(d.abs(path), map[string]bool{}) {
+		err.Value = Redacted
+	}
+	return err
+}
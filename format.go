@@ -0,0 +1,157 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Format returns the canonical textual form of the specified value, as
+// accepted by the typed accessor for the value's type, such as
+// [DeafAdder.GetDuration] for time.Duration values: a string for scalar values
+// and a []string for slice values. Format supports the same types as [Get],
+// including user-defined types registered using [RegisterValue]. As with Get,
+// int values are formatted for [DeafAdder.GetInt] as well as
+// [DeafAdder.GetCount]. For []byte values, use [FormatBytesBase64] or
+// [FormatBytesHex] instead. Secret values cannot be formatted. Similar to
+// [DeafAdder.GetIPMask], only IPv4 net.IPMask values are supported.
+func Format(v any) (any, error) {
+	typ := reflect.TypeOf(v)
+	formatters.RLock()
+	format, ok := formatters.m[typ]
+	formatters.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no formatter registered for type %v", typ)
+	}
+	return format(v)
+}
+
+// FormatBytesBase64 returns the canonical textual form of the specified []byte
+// value as accepted by [DeafAdder.GetBytesBase64].
+func FormatBytesBase64(v []byte) string {
+	s, _ := text(v, (*pflag.FlagSet).BytesBase64, true)
+	return s.(string)
+}
+
+// FormatBytesHex returns the canonical textual form of the specified []byte
+// value as accepted by [DeafAdder.GetBytesHex].
+func FormatBytesHex(v []byte) string {
+	s, _ := text(v, (*pflag.FlagSet).BytesHex, true)
+	return s.(string)
+}
+
+// formatters maps types to functions returning the canonical textual forms of
+// values of these types.
+var formatters = struct {
+	sync.RWMutex
+	m map[reflect.Type]func(v any) (any, error)
+}{
+	m: map[reflect.Type]func(v any) (any, error){},
+}
+
+// registerFormat registers the specified format function for its type T.
+func registerFormat[T any](format func(T) (any, error)) {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.m[reflect.TypeFor[T]()] = func(v any) (any, error) {
+		return format(v.(T))
+	}
+}
+
+// flagFormat returns a format function for values of type T, using the pflag
+// conversion rules of the specified flag constructor.
+func flagFormat[T any](fn func(*pflag.FlagSet, string, T, string) *T, treatAsScalar bool) func(T) (any, error) {
+	return func(v T) (any, error) {
+		return text(v, fn, treatAsScalar)
+	}
+}
+
+// stringFormat returns a format function for values of type T, using the
+// specified string function.
+func stringFormat[T any](str func(T) string) func(T) (any, error) {
+	return func(v T) (any, error) {
+		return str(v), nil
+	}
+}
+
+// sliceFormat returns a format function for []T values, using the specified
+// string function for the individual elements.
+func sliceFormat[T any](str func(T) string) func([]T) (any, error) {
+	return func(v []T) (any, error) {
+		sl := make([]string, len(v))
+		for idx, element := range v {
+			sl[idx] = str(element)
+		}
+		return sl, nil
+	}
+}
+
+func init() {
+	registerFormat(flagFormat((*pflag.FlagSet).Bool, false))
+	registerFormat(flagFormat(byteSizeConstructor, false))
+	registerFormat(flagFormat(byteSizeSliceConstructor, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Duration, false))
+	registerFormat(flagFormat((*pflag.FlagSet).DurationSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Float32, false))
+	registerFormat(sliceFormat(formatFloat32))
+	registerFormat(flagFormat((*pflag.FlagSet).Float64, false))
+	registerFormat(sliceFormat(formatFloat64))
+	registerFormat(flagFormat((*pflag.FlagSet).Int, false))
+	registerFormat(flagFormat((*pflag.FlagSet).IntSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int8, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int16, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int32, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int32Slice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int64, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Int64Slice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).IP, true))
+	registerFormat(flagFormat((*pflag.FlagSet).IPSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).IPMask, true))
+	registerFormat(flagFormat((*pflag.FlagSet).IPNet, false))
+	registerFormat(flagFormat((*pflag.FlagSet).IPNetSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).String, false))
+	registerFormat(flagFormat((*pflag.FlagSet).StringSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Uint, false))
+	registerFormat(flagFormat((*pflag.FlagSet).UintSlice, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Uint8, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Uint16, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Uint32, false))
+	registerFormat(flagFormat((*pflag.FlagSet).Uint64, false))
+
+	registerFormat(stringFormat(netip.Addr.String))
+	registerFormat(sliceFormat(netip.Addr.String))
+	registerFormat(stringFormat(netip.Prefix.String))
+	registerFormat(sliceFormat(netip.Prefix.String))
+	registerFormat(stringFormat(netip.AddrPort.String))
+	registerFormat(sliceFormat(netip.AddrPort.String))
+
+	registerFormat(stringFormat(formatTime))
+	registerFormat(sliceFormat(formatTime))
+	registerFormat(stringFormat((*time.Location).String))
+	registerFormat(stringFormat((*Schedule).String))
+
+	registerFormat(stringFormat((*url.URL).String))
+	registerFormat(sliceFormat((*url.URL).String))
+	registerFormat(stringFormat((*regexp.Regexp).String))
+	registerFormat(sliceFormat((*regexp.Regexp).String))
+}
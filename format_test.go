@@ -0,0 +1,241 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+// roundtrip formats the specified value, stores the formatted value, and then
+// returns the value retrieved using the specified accessor.
+func roundtrip[T any](v T, get func(*DeafAdder, string) (T, error)) T {
+	GinkgoHelper()
+	d := New(koanf.New("."))
+	Expect(d.Set("v", Successful(Format(v)))).To(Succeed())
+	return Successful(get(d, "v"))
+}
+
+// slice returns a slice of random length with random elements.
+func slice[T any](rnd *rand.Rand, element func() T) []T {
+	sl := make([]T, rnd.IntN(5))
+	for idx := range sl {
+		sl[idx] = element()
+	}
+	return sl
+}
+
+var _ = Describe("formatting values", func() {
+
+	const iterations = 200
+
+	var rnd *rand.Rand
+
+	BeforeEach(func() {
+		rnd = rand.New(rand.NewPCG(uint64(GinkgoRandomSeed()), 42))
+	})
+
+	randomString := func() string {
+		const alphabet = `abcXYZ019 ,;:"'[]{}#-_=%$\` + "\t\n"
+		b := make([]byte, rnd.IntN(12))
+		for idx := range b {
+			b[idx] = alphabet[rnd.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	randomBytes := func() []byte {
+		b := make([]byte, rnd.IntN(16))
+		for idx := range b {
+			b[idx] = byte(rnd.Uint32())
+		}
+		return b
+	}
+	randomIP := func() net.IP {
+		if rnd.IntN(2) == 0 {
+			return net.IPv4(byte(rnd.Uint32()), byte(rnd.Uint32()), byte(rnd.Uint32()), byte(rnd.Uint32()))
+		}
+		ip := make(net.IP, net.IPv6len)
+		for idx := range ip {
+			ip[idx] = byte(rnd.Uint32())
+		}
+		ip[0] = 0x20 // avoid IPv4-mapped addresses
+		return ip
+	}
+	randomIPNet := func() net.IPNet {
+		ip := randomIP()
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		mask := net.CIDRMask(rnd.IntN(bits+1), bits)
+		return net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	}
+	randomFloat64 := func() float64 {
+		return math.Float64frombits(rnd.Uint64()&^(0x7ff<<52)) * math.Pow(10, float64(rnd.IntN(600)-300))
+	}
+	randomTime := func() time.Time {
+		return time.Unix(rnd.Int64N(1<<34), rnd.Int64N(1e9)).UTC()
+	}
+
+	It("round-trips integer, float, and bool values", func() {
+		for range iterations {
+			i64 := int64(rnd.Uint64())
+			u64 := rnd.Uint64()
+			Expect(roundtrip(int(i64), (*DeafAdder).GetInt)).To(Equal(int(i64)))
+			Expect(roundtrip(int(i64), (*DeafAdder).GetCount)).To(Equal(int(i64)))
+			Expect(roundtrip(int8(i64), (*DeafAdder).GetInt8)).To(Equal(int8(i64)))
+			Expect(roundtrip(int16(i64), (*DeafAdder).GetInt16)).To(Equal(int16(i64)))
+			Expect(roundtrip(int32(i64), (*DeafAdder).GetInt32)).To(Equal(int32(i64)))
+			Expect(roundtrip(i64, (*DeafAdder).GetInt64)).To(Equal(i64))
+			Expect(roundtrip(uint(u64), (*DeafAdder).GetUint)).To(Equal(uint(u64)))
+			Expect(roundtrip(uint8(u64), (*DeafAdder).GetUint8)).To(Equal(uint8(u64)))
+			Expect(roundtrip(uint16(u64), (*DeafAdder).GetUint16)).To(Equal(uint16(u64)))
+			Expect(roundtrip(uint32(u64), (*DeafAdder).GetUint32)).To(Equal(uint32(u64)))
+			Expect(roundtrip(u64, (*DeafAdder).GetUint64)).To(Equal(u64))
+			b := rnd.IntN(2) == 1
+			Expect(roundtrip(b, (*DeafAdder).GetBool)).To(Equal(b))
+
+			f64 := randomFloat64()
+			Expect(roundtrip(f64, (*DeafAdder).GetFloat64)).To(Equal(f64))
+			Expect(roundtrip(float32(f64), (*DeafAdder).GetFloat32)).To(Equal(float32(f64)))
+
+			ints := slice(rnd, func() int { return int(rnd.Int64()) - math.MaxInt/2 })
+			Expect(roundtrip(ints, (*DeafAdder).GetIntSlice)).To(Equal(ints))
+			i32s := slice(rnd, func() int32 { return int32(rnd.Uint32()) })
+			Expect(roundtrip(i32s, (*DeafAdder).GetInt32Slice)).To(Equal(i32s))
+			i64s := slice(rnd, func() int64 { return int64(rnd.Uint64()) })
+			Expect(roundtrip(i64s, (*DeafAdder).GetInt64Slice)).To(Equal(i64s))
+			uints := slice(rnd, func() uint { return uint(rnd.Uint64()) })
+			Expect(roundtrip(uints, (*DeafAdder).GetUintSlice)).To(Equal(uints))
+			f32s := slice(rnd, func() float32 { return float32(randomFloat64()) })
+			Expect(roundtrip(f32s, (*DeafAdder).GetFloat32Slice)).To(Equal(f32s))
+			f64s := slice(rnd, randomFloat64)
+			Expect(roundtrip(f64s, (*DeafAdder).GetFloat64Slice)).To(Equal(f64s))
+		}
+	})
+
+	It("round-trips string and bytes values", func() {
+		for range iterations {
+			s := randomString()
+			Expect(roundtrip(s, (*DeafAdder).GetString)).To(Equal(s))
+			strs := slice(rnd, randomString)
+			Expect(roundtrip(strs, (*DeafAdder).GetStringSlice)).To(Equal(strs))
+
+			b := randomBytes()
+			d := New(koanf.New("."))
+			Expect(d.Set("b64", FormatBytesBase64(b))).To(Succeed())
+			Expect(d.Set("hex", FormatBytesHex(b))).To(Succeed())
+			Expect(d.GetBytesBase64("b64")).To(Equal(b))
+			Expect(d.GetBytesHex("hex")).To(Equal(b))
+		}
+	})
+
+	It("round-trips durations and byte sizes", func() {
+		for range iterations {
+			dur := time.Duration(rnd.Int64())
+			Expect(roundtrip(dur, (*DeafAdder).GetDuration)).To(Equal(dur))
+			durs := slice(rnd, func() time.Duration { return time.Duration(rnd.Int64() - math.MaxInt64/2) })
+			Expect(roundtrip(durs, (*DeafAdder).GetDurationSlice)).To(Equal(durs))
+
+			size := ByteSize(rnd.Uint64() >> rnd.IntN(64) << rnd.IntN(40))
+			Expect(roundtrip(size, (*DeafAdder).GetByteSize)).To(Equal(size))
+			sizes := slice(rnd, func() ByteSize { return ByteSize(rnd.Uint64()) })
+			Expect(roundtrip(sizes, (*DeafAdder).GetByteSizeSlice)).To(Equal(sizes))
+		}
+	})
+
+	It("round-trips IP values", func() {
+		for range iterations {
+			ip := randomIP()
+			Expect(roundtrip(ip, (*DeafAdder).GetIP).Equal(ip)).To(BeTrue())
+			ips := slice(rnd, randomIP)
+			Expect(roundtrip(ips, (*DeafAdder).GetIPSlice)).To(HaveLen(len(ips)))
+			for idx, rip := range roundtrip(ips, (*DeafAdder).GetIPSlice) {
+				Expect(rip.Equal(ips[idx])).To(BeTrue())
+			}
+
+			ipnet := randomIPNet()
+			Expect(roundtrip(ipnet, (*DeafAdder).GetIPNet)).To(Equal(ipnet))
+			mask := net.CIDRMask(rnd.IntN(33), 32) // IPv4 masks only
+			Expect(roundtrip(mask, (*DeafAdder).GetIPMask)).To(Equal(mask))
+			ipnets := slice(rnd, randomIPNet)
+			Expect(roundtrip(ipnets, (*DeafAdder).GetIPNetSlice)).To(HaveLen(len(ipnets)))
+			for idx, ripnet := range roundtrip(ipnets, (*DeafAdder).GetIPNetSlice) {
+				Expect(ripnet.String()).To(Equal(ipnets[idx].String()))
+			}
+
+			addr, _ := netip.AddrFromSlice(ip)
+			addr = addr.Unmap()
+			Expect(roundtrip(addr, (*DeafAdder).GetAddr)).To(Equal(addr))
+			prefix := netip.PrefixFrom(addr, rnd.IntN(addr.BitLen()+1)).Masked()
+			Expect(roundtrip(prefix, (*DeafAdder).GetPrefix)).To(Equal(prefix))
+			addrport := netip.AddrPortFrom(addr, uint16(rnd.Uint32()))
+			Expect(roundtrip([]netip.AddrPort{addrport}, (*DeafAdder).GetAddrPortSlice)).To(
+				ConsistOf(addrport))
+		}
+	})
+
+	It("round-trips time values", func() {
+		for range iterations {
+			t := randomTime()
+			Expect(roundtrip(t, func(d *DeafAdder, path string) (time.Time, error) {
+				return d.GetTime(path)
+			})).To(Equal(t))
+			ts := slice(rnd, randomTime)
+			Expect(roundtrip(ts, func(d *DeafAdder, path string) ([]time.Time, error) {
+				return d.GetTimeSlice(path)
+			})).To(Equal(ts))
+		}
+		for _, name := range []string{"UTC", "Europe/Berlin", "America/New_York"} {
+			loc := Successful(time.LoadLocation(name))
+			Expect(roundtrip(loc, (*DeafAdder).GetLocation).String()).To(Equal(name))
+		}
+	})
+
+	It("round-trips URLs and regular expressions", func() {
+		for _, s := range []string{
+			"https://example.org/foo?bar=baz#frag",
+			"http://[fe80::1%25eth0]:8080/",
+			"file:///etc/passwd",
+			"mailto:root@localhost",
+		} {
+			u := Successful(url.Parse(s))
+			Expect(roundtrip(u, func(d *DeafAdder, path string) (*url.URL, error) {
+				return d.GetURL(path)
+			})).To(Equal(u))
+		}
+		res := []*regexp.Regexp{regexp.MustCompile(`^a+,b*$`), regexp.MustCompile(`[[:alpha:]]{2,}`)}
+		Expect(Successful(Format(res))).To(Equal([]string{`^a+,b*$`, `[[:alpha:]]{2,}`}))
+		Expect(roundtrip(res[0], (*DeafAdder).GetRegexp).String()).To(Equal(res[0].String()))
+	})
+
+	It("rejects unsupported types", func() {
+		Expect(Format(Secret{})).Error().To(MatchError(
+			"no formatter registered for type deafadder.Secret"))
+		Expect(Format([]byte{})).Error().To(HaveOccurred())
+	})
+
+})
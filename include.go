@@ -0,0 +1,192 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+	"github.com/thediveo/deafadder/sub"
+)
+
+// WithIncludes enables include directives in configuration files loaded using
+// [DeafAdder.LoadFile], using the specified key, defaulting to “include” if
+// empty. An include directive lists one or more configuration files to be
+// loaded and merged at the position of the include directive, for instance:
+//
+//	db:
+//	  include: db.yaml
+//	include:
+//	  - base.yaml
+//	  - conf.d/*.yaml
+//	  - ?local.yaml
+//
+// Relative file names are resolved against the directory of the including
+// file. File names can be glob patterns, matching zero or more files in
+// lexical order. File names prefixed with “?” are optional and silently
+// skipped if missing. The settings of the including file take precedence over
+// the included settings, and later includes take precedence over earlier
+// includes. Include cycles are reported as errors.
+func WithIncludes(key string) Option {
+	return func(d *DeafAdder) {
+		if key == "" {
+			key = "include"
+		}
+		d.opts.includeKey = key
+	}
+}
+
+// loadTree reads and parses the configuration file with the specified absolute
+// name, processing include directives if enabled. It returns the resulting
+// configuration map as well as the names of the files supplying the individual
+// (flattened) configuration settings. The chain lists the files currently
+// being loaded, in order to detect include cycles.
+func (d *DeafAdder) loadTree(name string, parser koanf.Parser, chain []string) (map[string]any, map[string]string, error) {
+	chain = append(slices.Clone(chain), name)
+	if slices.Contains(chain[:len(chain)-1], name) {
+		return nil, nil, fmt.Errorf("include cycle %s", strings.Join(chain, " -> "))
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	mp, err := parser.Unmarshal(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse %s: %w", name, err)
+	}
+
+	tree := map[string]any{}
+	origins := map[string]string{}
+	delim := d.Delim()
+	if key := d.settings().includeKey; key != "" {
+		sites, err := includeSites(mp, key, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid include in %s: %w", name, err)
+		}
+		for _, site := range sites {
+			for _, include := range site.includes {
+				names, err := includedFiles(filepath.Dir(name), include)
+				if err != nil {
+					return nil, nil, fmt.Errorf("cannot include %s in %s: %w", include, name, err)
+				}
+				for _, included := range names {
+					submp, suborigins, err := d.loadTree(included, parser, chain)
+					if err != nil {
+						return nil, nil, err
+					}
+					sub.Merge(site.path)(submp, tree)
+					prefix := strings.Join(site.path, delim)
+					for key, origin := range suborigins {
+						if prefix != "" {
+							key = prefix + delim + key
+						}
+						origins[key] = origin
+					}
+				}
+			}
+		}
+	}
+
+	maps.Merge(mp, tree)
+	flat, _ := maps.Flatten(mp, nil, delim)
+	for key := range flat {
+		origins[key] = name
+	}
+	return tree, origins, nil
+}
+
+// includeSite is the position of an include directive inside a configuration
+// map, together with the files to include there.
+type includeSite struct {
+	path     []string
+	includes []string
+}
+
+// includeSites returns the include directives found in the specified
+// configuration map and its nested maps, removing them from the maps. The
+// include sites are returned in deterministic order, with the include sites of
+// a map preceding those of its nested maps.
+func includeSites(mp map[string]any, key string, path []string) ([]includeSite, error) {
+	var sites []includeSite
+	if value, ok := mp[key]; ok {
+		delete(mp, key)
+		var includes []string
+		switch v := value.(type) {
+		case string:
+			includes = []string{v}
+		case []any:
+			for _, element := range v {
+				include, ok := element.(string)
+				if !ok {
+					return nil, fmt.Errorf("include at %q must be a file name or list of file names",
+						strings.Join(append(path, key), "."))
+				}
+				includes = append(includes, include)
+			}
+		default:
+			return nil, fmt.Errorf("include at %q must be a file name or list of file names",
+				strings.Join(append(path, key), "."))
+		}
+		sites = append(sites, includeSite{path: slices.Clone(path), includes: includes})
+	}
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		child, ok := mp[k].(map[string]any)
+		if !ok {
+			continue
+		}
+		childSites, err := includeSites(child, key, append(slices.Clone(path), k))
+		if err != nil {
+			return nil, err
+		}
+		sites = append(sites, childSites...)
+	}
+	return sites, nil
+}
+
+// includedFiles returns the absolute names of the files matching the specified
+// include, resolving relative names against the specified directory.
+func includedFiles(dir string, include string) ([]string, error) {
+	include, optional := strings.CutPrefix(include, "?")
+	if !filepath.IsAbs(include) {
+		include = filepath.Join(dir, include)
+	}
+	if strings.ContainsAny(include, `*?[\`) {
+		names, err := filepath.Glob(include)
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(names)
+		return names, nil
+	}
+	if _, err := os.Stat(include); err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []string{include}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"path/filepath"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("including configuration files", func() {
+
+	It("ignores include directives by default", func() {
+		tmp := GinkgoT().TempDir()
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(writeFile(tmp, "config.yaml", `include: nada.yaml`), yaml.Parser())).To(Succeed())
+		Expect(d.GetString("include")).To(Equal("nada.yaml"))
+	})
+
+	It("includes files", func() {
+		tmp := GinkgoT().TempDir()
+		writeFile(tmp, "app/base.yaml", `
+log:
+  level: info
+  format: text
+`)
+		writeFile(tmp, "app/conf.d/10-log.yaml", `
+log:
+  level: debug
+`)
+		fragment := writeFile(tmp, "app/conf.d/20-http.yaml", `
+http:
+  listen: ":8080"
+`)
+		dbconf := writeFile(tmp, "app/db/db.yaml", `
+host: localhost
+port: 5432
+include: ../secrets/db.yaml
+`)
+		secrets := writeFile(tmp, "app/secrets/db.yaml", `
+password: sesame
+`)
+		config := writeFile(tmp, "app/config.yaml", `
+include:
+  - base.yaml
+  - conf.d/*.yaml
+  - ?local.yaml
+log:
+  format: json
+db:
+  include: db/db.yaml
+  port: 5433
+`)
+		d := New(koanf.New("."), WithIncludes(""))
+		Expect(d.LoadFile(config, yaml.Parser())).To(Succeed())
+		Expect(d.Exists("include")).To(BeFalse())
+		Expect(d.GetString("log.level")).To(Equal("debug"))
+		Expect(d.GetString("log.format")).To(Equal("json"))
+		Expect(d.GetString("http.listen")).To(Equal(":8080"))
+		Expect(d.GetString("db.host")).To(Equal("localhost"))
+		Expect(d.GetInt("db.port")).To(Equal(5433))
+		Expect(d.GetString("db.password")).To(Equal("sesame"))
+
+		Expect(d.Origin("log.level")).To(Equal(filepath.Join(tmp, "app/conf.d/10-log.yaml")))
+		Expect(d.Origin("log.format")).To(Equal(config))
+		Expect(d.Origin("http.listen")).To(Equal(fragment))
+		Expect(d.Origin("db.host")).To(Equal(dbconf))
+		Expect(d.Origin("db.port")).To(Equal(config))
+		Expect(d.Origin("db.password")).To(Equal(secrets))
+	})
+
+	It("uses a custom include key", func() {
+		tmp := GinkgoT().TempDir()
+		writeFile(tmp, "other.yaml", `foo: bar`)
+		d := New(koanf.New("."), WithIncludes("$include"))
+		Expect(d.LoadFile(writeFile(tmp, "config.yaml", `$include: other.yaml`), yaml.Parser())).To(Succeed())
+		Expect(d.GetString("foo")).To(Equal("bar"))
+	})
+
+	It("reports include errors", func() {
+		tmp := GinkgoT().TempDir()
+		d := New(koanf.New("."), WithIncludes(""))
+
+		a := writeFile(tmp, "a.yaml", `include: b.yaml`)
+		b := writeFile(tmp, "b.yaml", `include: [c.yaml]`)
+		writeFile(tmp, "c.yaml", `include: a.yaml`)
+		Expect(d.LoadFile(a, yaml.Parser())).To(MatchError(ContainSubstring(
+			"include cycle " + a + " -> " + b)))
+
+		Expect(d.LoadFile(writeFile(tmp, "missing.yaml", `include: nada.yaml`), yaml.Parser())).To(
+			MatchError(ContainSubstring("cannot include nada.yaml")))
+		Expect(d.LoadFile(writeFile(tmp, "invalid.yaml", `include: 42`), yaml.Parser())).To(
+			MatchError(ContainSubstring(`include at "include" must be a file name`)))
+		Expect(d.LoadFile(writeFile(tmp, "invalid-list.yaml", "foo:\n  include: [42]"), yaml.Parser())).To(
+			MatchError(ContainSubstring(`include at "foo.include" must be a file name`)))
+		Expect(d.LoadFile(writeFile(tmp, "broken.yaml", `include: broken-fragment.yaml`), yaml.Parser())).NotTo(
+			Succeed())
+		writeFile(tmp, "broken-fragment.yaml", `foo: [bar`)
+		Expect(d.LoadFile(filepath.Join(tmp, "broken.yaml"), yaml.Parser())).To(
+			MatchError(ContainSubstring("cannot parse " + filepath.Join(tmp, "broken-fragment.yaml"))))
+	})
+
+})
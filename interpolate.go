@@ -0,0 +1,159 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// WithInterpolation enables expanding references inside textual configuration
+// values before conversion:
+//   - “${path}” expands to the (interpolated) value of the configuration
+//     setting with the given name,
+//   - “${env:NAME}” expands to the value of the environment variable NAME,
+//   - “$$” expands to a literal “$”.
+//
+// Reference cycles as well as references to missing configuration settings or
+// unset environment variables are reported as errors, showing the chain of
+// references.
+func WithInterpolation() Option {
+	return func(d *DeafAdder) {
+		d.opts.interpolate = true
+	}
+}
+
+// interpolate the specified configuration value, which can be either a scalar
+// value or a slice of scalar values. The chain lists the names of the
+// configuration settings currently being interpolated, in order to detect
+// reference cycles.
+func (d *DeafAdder) interpolate(value any, chain []string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return d.interpolateString(v, chain)
+	case []any:
+		out := make([]any, len(v))
+		for idx, element := range v {
+			ev, err := d.interpolate(element, chain)
+			if err != nil {
+				return nil, err
+			}
+			out[idx] = ev
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// interpolateString expands all references inside the specified string.
+func (d *DeafAdder) interpolateString(s string, chain []string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		before, after, found := strings.Cut(s, "$")
+		b.WriteString(before)
+		if !found {
+			return b.String(), nil
+		}
+		switch {
+		case strings.HasPrefix(after, "$"):
+			b.WriteByte('$')
+			s = after[1:]
+		case strings.HasPrefix(after, "{"):
+			ref, rest, ok := strings.Cut(after[1:], "}")
+			if !ok {
+				return "", fmt.Errorf("unterminated reference in %q (%s)",
+					s, strings.Join(chain, " -> "))
+			}
+			expanded, err := d.expandReference(ref, chain)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(expanded)
+			s = rest
+		default:
+			b.WriteByte('$')
+			s = after
+		}
+	}
+}
+
+// expandReference returns the expansion of the specified reference, which is
+// either an environment variable reference or the name of another
+// configuration setting.
+func (d *DeafAdder) expandReference(ref string, chain []string) (string, error) {
+	if name, ok := strings.CutPrefix(ref, "env:"); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s not set (%s -> ${%s})",
+				name, strings.Join(chain, " -> "), ref)
+		}
+		return value, nil
+	}
+	chain = append(slices.Clone(chain), ref)
+	if slices.Contains(chain[:len(chain)-1], ref) {
+		return "", fmt.Errorf("reference cycle %s", strings.Join(chain, " -> "))
+	}
+	value, err := d.raw(ref)
+	if err != nil {
+		return "", fmt.Errorf("%w (%s)", err, strings.Join(chain, " -> "))
+	}
+	if value == nil {
+		return "", fmt.Errorf("no such configuration setting %s (%s)",
+			ref, strings.Join(chain, " -> "))
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Slice, reflect.Map:
+		return "", fmt.Errorf("cannot interpolate non-scalar configuration setting %s (%s)",
+			ref, strings.Join(chain, " -> "))
+	}
+	value, err = d.interpolate(value, chain)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// references returns the names of the configuration settings referenced inside
+// the specified string, ignoring environment variable references.
+func references(s string) []string {
+	var refs []string
+	for {
+		_, after, found := strings.Cut(s, "$")
+		if !found {
+			return refs
+		}
+		switch {
+		case strings.HasPrefix(after, "$"):
+			s = after[1:]
+		case strings.HasPrefix(after, "{"):
+			ref, rest, ok := strings.Cut(after[1:], "}")
+			if !ok {
+				return refs
+			}
+			if !strings.HasPrefix(ref, "env:") {
+				refs = append(refs, ref)
+			}
+			s = rest
+		default:
+			s = after
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"net/netip"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("interpolation", func() {
+
+	const s = `
+host: example.org
+port: 8443
+api: https://${host}:${port}/api
+apis:
+  - ${api}/v1
+  - ${api}/v2
+addr: ${env:DEAFADDER_TEST_ADDR}:${port}
+price: $$42 and $5
+nested: ${api}
+cycle-a: ${cycle-b}
+cycle-b: x${cycle-c}
+cycle-c: ${cycle-a}
+missing: ${nada}
+deep-missing: ${missing}
+unset-env: ${env:DEAFADDER_TEST_NADA}
+unterminated: ${host
+list-ref: ${apis}
+`
+
+	loadWith := func(opts ...Option) *DeafAdder {
+		GinkgoHelper()
+		d := New(koanf.New("."), opts...)
+		Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+		return d
+	}
+
+	It("doesn't interpolate by default", func() {
+		d := loadWith()
+		Expect(d.GetString("api")).To(Equal("https://${host}:${port}/api"))
+	})
+
+	It("interpolates values", func() {
+		GinkgoT().Setenv("DEAFADDER_TEST_ADDR", "10.0.0.1")
+		d := loadWith(WithInterpolation())
+		Expect(d.GetString("api")).To(Equal("https://example.org:8443/api"))
+		Expect(d.GetString("nested")).To(Equal("https://example.org:8443/api"))
+		Expect(d.GetStringSlice("apis")).To(Equal([]string{
+			"https://example.org:8443/api/v1",
+			"https://example.org:8443/api/v2",
+		}))
+		Expect(d.GetAddrPort("addr")).To(Equal(netip.MustParseAddrPort("10.0.0.1:8443")))
+		Expect(d.GetString("price")).To(Equal("$42 and $5"))
+	})
+
+	It("reports interpolation errors with their reference chains", func() {
+		d := loadWith(WithInterpolation())
+		Expect(d.GetString("cycle-a")).Error().To(MatchError(
+			"invalid value for configuration setting cycle-a: reference cycle cycle-a -> cycle-b -> cycle-c -> cycle-a"))
+		Expect(d.GetString("deep-missing")).Error().To(MatchError(ContainSubstring(
+			"no such configuration setting nada (deep-missing -> missing -> nada)")))
+		Expect(d.GetString("unset-env")).Error().To(MatchError(ContainSubstring(
+			"environment variable DEAFADDER_TEST_NADA not set (unset-env -> ${env:DEAFADDER_TEST_NADA})")))
+		Expect(d.GetString("unterminated")).Error().To(MatchError(ContainSubstring(
+			"unterminated reference")))
+		Expect(d.GetString("list-ref")).Error().To(MatchError(ContainSubstring(
+			"cannot interpolate non-scalar configuration setting apis")))
+	})
+
+})
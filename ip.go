@@ -0,0 +1,59 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// parseStrictIP parses a textual IP address, rejecting empty and invalid
+// addresses.
+func parseStrictIP(s string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	return ip, nil
+}
+
+// parseStrictIPNet parses a textual IP network in CIDR notation, rejecting
+// networks with host bits set, such as “10.0.0.1/8”.
+func parseStrictIPNet(s string) (net.IPNet, error) {
+	ip, ipnet, err := net.ParseCIDR(strings.TrimSpace(s))
+	if err != nil {
+		return net.IPNet{}, err
+	}
+	if !ip.Equal(ipnet.IP) {
+		return net.IPNet{}, fmt.Errorf("IP network %q has host bits set", s)
+	}
+	return *ipnet, nil
+}
+
+// parseStrictIPMask parses a textual IPv4 mask in either dotted decimal or
+// hexadecimal notation, rejecting non-canonical masks such as “255.0.255.0”.
+func parseStrictIPMask(s string) (net.IPMask, error) {
+	mask := pflag.ParseIPv4Mask(strings.TrimSpace(s))
+	if mask == nil {
+		return nil, fmt.Errorf("invalid IP mask %q", s)
+	}
+	if _, bits := mask.Size(); bits == 0 {
+		return nil, fmt.Errorf("non-canonical IP mask %q", s)
+	}
+	return mask, nil
+}
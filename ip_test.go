@@ -0,0 +1,94 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"net"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("IP conversion", func() {
+
+	const s = `
+ip: 127.0.0.1
+ip-empty: ""
+ip-bad: 127.0.0.666
+ips:
+  - 127.0.0.1
+  - ""
+ipnet: 10.0.0.0/8
+ipnet-hostbits: 10.0.0.1/8
+ipnets:
+  - 10.0.0.0/8
+  - 10.0.0.1/8
+mask: 255.255.255.0
+mask-hex: ffffff00
+mask-noncanonical: 255.0.255.0
+`
+
+	loadWith := func(opts ...Option) *DeafAdder {
+		GinkgoHelper()
+		d := New(koanf.New("."), opts...)
+		Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+		return d
+	}
+
+	It("silently accepts questionable values in non-strict mode", func() {
+		d := loadWith()
+		Expect(d.GetIP("ip-empty")).To(BeNil())
+		_, ipnet := Successful2R(net.ParseCIDR("10.0.0.0/8"))
+		Expect(d.GetIPNet("ipnet-hostbits")).To(Equal(*ipnet))
+		Expect(d.GetIPMask("mask-noncanonical")).To(Equal(net.IPv4Mask(255, 0, 255, 0)))
+	})
+
+	It("reports invalid values in strict mode", func() {
+		d := loadWith(WithStrictIP())
+
+		Expect(d.GetIP("ip")).To(Equal(net.ParseIP("127.0.0.1")))
+		Expect(d.GetIPSlice("ips")).Error().To(MatchError(ContainSubstring(
+			`invalid value for configuration setting ips[1]: invalid IP address ""`)))
+		_, ipnet := Successful2R(net.ParseCIDR("10.0.0.0/8"))
+		Expect(d.GetIPNet("ipnet")).To(Equal(*ipnet))
+		Expect(d.GetIPNetSlice("ipnets")).Error().To(MatchError(ContainSubstring("ipnets[1]")))
+		Expect(d.GetIPMask("mask")).To(Equal(net.IPv4Mask(255, 255, 255, 0)))
+		Expect(d.GetIPMask("mask-hex")).To(Equal(net.IPv4Mask(255, 255, 255, 0)))
+
+		var cerr *ConversionError
+		for _, path := range []string{"ip-empty", "ip-bad"} {
+			_, err := d.GetIP(path)
+			Expect(err).To(MatchError(ContainSubstring("invalid IP address")))
+			Expect(err).To(BeAssignableToTypeOf(cerr))
+			Expect(err.(*ConversionError).Path).To(Equal(path))
+		}
+		Expect(d.GetIPNet("ipnet-hostbits")).Error().To(MatchError(ContainSubstring("host bits set")))
+		Expect(d.GetIPMask("mask-noncanonical")).Error().To(MatchError(ContainSubstring("non-canonical IP mask")))
+		Expect(d.GetIPMask("ip-bad")).Error().To(MatchError(ContainSubstring("invalid IP mask")))
+	})
+
+	It("checks values that already are of the target type in strict mode", func() {
+		d := New(koanf.New("."), WithStrictIP())
+		Expect(d.Set("mask", net.IPv4Mask(255, 0, 255, 0))).To(Succeed())
+		Expect(d.Get("mask")).To(BeAssignableToTypeOf(net.IPMask{}))
+		Expect(d.GetIPMask("mask")).Error().To(MatchError(ContainSubstring("invalid value")))
+	})
+
+})
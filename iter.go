@@ -0,0 +1,99 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Walk returns an iterator over the names and raw values of all leaf
+// configuration settings at or below the specified path, sorted by their
+// names. An empty path walks all configuration settings.
+func (d *DeafAdder) Walk(path string) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		k := d.current()
+		for _, key := range keys(k, path) {
+			if !yield(key, k.Get(key)) {
+				return
+			}
+		}
+	}
+}
+
+// Children returns an iterator over the names and raw values of the immediate
+// children of the configuration map at the specified path, sorted by their
+// names. Child values that are maps themselves are yielded as
+// map[string]any. If there is no configuration map at the specified path,
+// the iterator yields nothing.
+func (d *DeafAdder) Children(path string) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		k := d.current()
+		var mp map[string]any
+		if path == "" {
+			mp = k.Raw()
+		} else {
+			mp, _ = k.Get(path).(map[string]any)
+		}
+		for _, name := range slices.Sorted(maps.Keys(mp)) {
+			if !yield(name, mp[name]) {
+				return
+			}
+		}
+	}
+}
+
+// Setting is a configuration setting's name and its typed value.
+type Setting[T any] struct {
+	Path  string
+	Value T
+}
+
+// Leaves returns an iterator over all leaf configuration settings at or below
+// the specified path, sorted by their names, converting their values using the
+// specified typed accessor, such as (*DeafAdder).GetUint64. For settings that
+// cannot be converted, the iterator yields the setting's name together with
+// the conversion error; iteration then continues with the next setting.
+//
+//	for quota, err := range deafadder.Leaves(d, "quotas", (*deafadder.DeafAdder).GetUint64) {
+//		...
+//	}
+func Leaves[T any](d *DeafAdder, path string, get func(*DeafAdder, string) (T, error)) iter.Seq2[Setting[T], error] {
+	return func(yield func(Setting[T], error) bool) {
+		for _, key := range keys(d.current(), path) {
+			v, err := get(d, key)
+			if !yield(Setting[T]{Path: key, Value: v}, err) {
+				return
+			}
+		}
+	}
+}
+
+// keys returns the sorted names of all leaf configuration settings at or below
+// the specified path.
+func keys(k *koanf.Koanf, path string) []string {
+	keys := k.Keys()
+	if path == "" {
+		return keys
+	}
+	prefix := path + k.Delim()
+	return slices.DeleteFunc(keys, func(key string) bool {
+		return key != path && !strings.HasPrefix(key, prefix)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("iterating configuration settings", func() {
+
+	const conf = `
+quotas:
+  bob: 20
+  alice: 10
+  mallory: lots
+quotation: 42
+limits:
+  mem:
+    max: 1G
+`
+
+	It("walks leaf settings under a path", func() {
+		d := load(conf)
+		var keys []string
+		for key, value := range d.Walk("quotas") {
+			keys = append(keys, key)
+			Expect(value).NotTo(BeNil())
+		}
+		Expect(keys).To(Equal([]string{"quotas.alice", "quotas.bob", "quotas.mallory"}))
+
+		keys = nil
+		for key := range d.Walk("") {
+			keys = append(keys, key)
+			if len(keys) == 2 {
+				break
+			}
+		}
+		Expect(keys).To(Equal([]string{"limits.mem.max", "quotas.alice"}))
+	})
+
+	It("iterates immediate children", func() {
+		d := load(conf)
+		var names []string
+		for name, value := range d.Children("limits") {
+			names = append(names, name)
+			Expect(value).To(HaveKey("max"))
+		}
+		Expect(names).To(Equal([]string{"mem"}))
+
+		names = nil
+		for name := range d.Children("") {
+			names = append(names, name)
+		}
+		Expect(names).To(Equal([]string{"limits", "quotas", "quotation"}))
+
+		for range d.Children("quotation") {
+			Fail("iterated a non-map")
+		}
+	})
+
+	It("converts leaf values", func() {
+		d := load(conf)
+		quotas := map[string]uint64{}
+		var errs []error
+		for setting, err := range Leaves(d, "quotas", (*DeafAdder).GetUint64) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			quotas[setting.Path] = setting.Value
+		}
+		Expect(quotas).To(Equal(map[string]uint64{"quotas.alice": 10, "quotas.bob": 20}))
+		Expect(errs).To(ConsistOf(MatchError(ContainSubstring(
+			"configuration setting quotas.mallory"))))
+
+		http := load(conf).Sub("limits")
+		for setting, err := range Leaves(http, "mem", (*DeafAdder).GetByteSize) {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(setting).To(Equal(Setting[ByteSize]{Path: "mem.max", Value: GB}))
+		}
+	})
+
+})
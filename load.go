@@ -0,0 +1,221 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// LoadFile loads the configuration file with the specified name using the
+// specified parser and merges it into the configuration, in the same way as
+// [koanf.Koanf.Load] does. Additionally, LoadFile records the file as the
+// origin of all configuration settings it supplies, so that, for instance,
+// [DeafAdder.GetPath] can resolve relative paths against the location of the
+// file.
+//
+// If includes are enabled using [WithIncludes], LoadFile additionally loads
+// and merges the included configuration files.
+//
+// Loading or setting configuration settings by other means, such as
+// [DeafAdder.Load] or [DeafAdder.SetString], forgets the origins of the
+// changed settings, as they no longer come from a configuration file.
+//
+// Please note that origins are recorded assuming that the file's settings get
+// merged at the root of the configuration.
+func (d *DeafAdder) LoadFile(name string, parser koanf.Parser, opts ...koanf.Option) error {
+	name, err := filepath.Abs(name)
+	if err != nil {
+		return err
+	}
+	mp, origins, err := d.loadTree(name, parser, nil)
+	if err != nil {
+		return err
+	}
+	return d.load(mp, origins, opts...)
+}
+
+// load merges the specified configuration map into the configuration and
+// records the origins of the merged configuration settings.
+func (d *DeafAdder) load(mp map[string]any, origins map[string]string, opts ...koanf.Option) error {
+	flat, _ := maps.Flatten(mp, nil, d.Delim())
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	return d.track(keys, func() error {
+		return d.Koanf.Load(mapProvider(mp), nil, opts...)
+	}, origins)
+}
+
+// Load merges the configuration from the specified provider, in the same way
+// as [koanf.Koanf.Load] does. Additionally, Load forgets the origins of all
+// configuration settings it changes, see also [DeafAdder.Origin].
+func (d *DeafAdder) Load(p koanf.Provider, pa koanf.Parser, opts ...koanf.Option) error {
+	if p == nil {
+		return errors.New("load received a nil provider")
+	}
+	var mp map[string]any
+	if pa == nil {
+		var err error
+		if mp, err = p.Read(); err != nil {
+			return err
+		}
+	} else {
+		b, err := p.ReadBytes()
+		if err != nil {
+			return err
+		}
+		if mp, err = pa.Unmarshal(b); err != nil {
+			return err
+		}
+	}
+	return d.load(mp, nil, opts...)
+}
+
+// Merge merges the configuration of the specified koanf.Koanf object, in the
+// same way as [koanf.Koanf.Merge] does, forgetting the origins of all
+// configuration settings it changes.
+func (d *DeafAdder) Merge(in *koanf.Koanf) error {
+	return d.track(in.Keys(), func() error { return d.Koanf.Merge(in) }, nil)
+}
+
+// MergeAt merges the configuration of the specified koanf.Koanf object at the
+// specified path, in the same way as [koanf.Koanf.MergeAt] does, forgetting the
+// origins of all configuration settings it changes.
+func (d *DeafAdder) MergeAt(in *koanf.Koanf, path string) error {
+	keys := in.Keys()
+	if path != "" {
+		for idx, key := range keys {
+			keys[idx] = path + d.Delim() + key
+		}
+	}
+	return d.track(keys, func() error { return d.Koanf.MergeAt(in, path) }, nil)
+}
+
+// Set sets the value of the configuration setting with the given name, in the
+// same way as [koanf.Koanf.Set] does, forgetting the origins of all
+// configuration settings it changes.
+func (d *DeafAdder) Set(key string, val any) error {
+	return d.track([]string{key}, func() error { return d.Koanf.Set(key, val) }, nil)
+}
+
+// Delete removes the configuration setting or subtree with the given name, in
+// the same way as [koanf.Koanf.Delete] does, forgetting the origins of the
+// removed configuration settings.
+func (d *DeafAdder) Delete(path string) {
+	_ = d.track([]string{path}, func() error { d.Koanf.Delete(path); return nil }, nil)
+}
+
+// track runs the specified configuration change touching the configuration
+// settings or subtrees with the specified names, forgetting the origins of
+// those actually changed, and then recording the specified origins. It
+// additionally invalidates the index of normalized keys. Only the
+// touched settings are compared, so that changes are tracked without
+// flattening the whole configuration.
+func (d *DeafAdder) track(keys []string, change func() error, origins map[string]string) error {
+	before := make([]any, len(keys))
+	for idx, key := range keys {
+		before[idx] = d.Koanf.Get(key)
+	}
+	err := change()
+	d.indexMu.Lock()
+	d.index = nil
+	d.indexMu.Unlock()
+	o := d.settings()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for idx, key := range keys {
+		if after := d.Koanf.Get(key); !reflect.DeepEqual(before[idx], after) {
+			d.forget(o, key, before[idx], after)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if o.origins == nil {
+		o.origins = map[string]string{}
+	}
+	for key, origin := range origins {
+		o.origins[d.abs(key)] = origin
+	}
+	return nil
+}
+
+// forget forgets the origins of the changed configuration setting or subtree
+// with the specified name, of its ancestors, and of all settings inside the
+// specified old and new values of the subtree. The caller must hold the
+// settings' lock.
+func (d *DeafAdder) forget(o *options, key string, values ...any) {
+	delim := d.Delim()
+	for path := key; ; {
+		delete(o.origins, d.abs(path))
+		cut := strings.LastIndex(path, delim)
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	var prefix []string
+	if key != "" {
+		prefix = []string{key}
+	}
+	for _, value := range values {
+		if mp, ok := value.(map[string]any); ok {
+			flat, _ := maps.Flatten(mp, prefix, delim)
+			for path := range flat {
+				delete(o.origins, d.abs(path))
+			}
+		}
+	}
+}
+
+// Origin returns the name of the configuration file that supplied the
+// configuration setting with the given name, or "" if unknown. For settings
+// inside list elements, such as “listeners[2].port”, Origin returns the name
+// of the configuration file that supplied the list.
+func (d *DeafAdder) Origin(path string) string {
+	o := d.settings()
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	path = d.abs(path)
+	for {
+		if origin, ok := o.origins[path]; ok {
+			return origin
+		}
+		cut := max(strings.LastIndex(path, d.Delim()), strings.LastIndex(path, "["))
+		if cut < 0 {
+			return ""
+		}
+		path = path[:cut]
+	}
+}
+
+// mapProvider implements [koanf.Provider] for an already parsed configuration
+// map.
+type mapProvider map[string]any
+
+func (m mapProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("mapProvider does not support ReadBytes")
+}
+
+func (m mapProvider) Read() (map[string]any, error) {
+	return maps.Copy(m), nil
+}
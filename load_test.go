@@ -0,0 +1,128 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// writeFile writes the specified contents into a file inside the given
+// directory, creating any missing intermediate directories, and returns the
+// file's name.
+func writeFile(dir, name, contents string) string {
+	GinkgoHelper()
+	name = filepath.Join(dir, name)
+	Expect(os.MkdirAll(filepath.Dir(name), 0o755)).To(Succeed())
+	Expect(os.WriteFile(name, []byte(contents), 0o644)).To(Succeed())
+	return name
+}
+
+var _ = Describe("loading configuration files", func() {
+
+	It("records origins", func() {
+		tmp := GinkgoT().TempDir()
+		first := writeFile(tmp, "first.yaml", `
+foo:
+  bar: 42
+  baz: 666
+`)
+		second := writeFile(tmp, "conf.d/second.yaml", `
+foo:
+  baz: 999
+`)
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(first, yaml.Parser())).To(Succeed())
+		Expect(d.LoadFile(second, yaml.Parser())).To(Succeed())
+		Expect(d.GetInt("foo.baz")).To(Equal(999))
+		Expect(d.Origin("foo.bar")).To(Equal(first))
+		Expect(d.Origin("foo.baz")).To(Equal(second))
+		Expect(d.Origin("foo.nada")).To(BeEmpty())
+	})
+
+	It("forgets origins of settings changed otherwise", func() {
+		tmp := GinkgoT().TempDir()
+		name := writeFile(tmp, "conf.d/conf.yaml", `
+foo:
+  cert: cert.pem
+  key: key.pem
+  ca: ca.pem
+`)
+		d := New(koanf.New("."), WithBaseDir("/base"))
+		Expect(d.LoadFile(name, yaml.Parser())).To(Succeed())
+		Expect(d.GetPath("foo.cert")).To(Equal(filepath.Join(tmp, "conf.d", "cert.pem")))
+
+		Expect(d.Load(mapProvider{"foo": map[string]any{"cert": "other.pem"}}, nil)).To(Succeed())
+		Expect(d.Origin("foo.cert")).To(BeEmpty())
+		Expect(d.GetPath("foo.cert")).To(Equal("/base/other.pem"))
+		Expect(d.Origin("foo.key")).To(Equal(name))
+
+		Expect(d.SetString("foo.key", "other.key")).To(Succeed())
+		Expect(d.Origin("foo.key")).To(BeEmpty())
+		Expect(d.GetPath("foo.key")).To(Equal("/base/other.key"))
+
+		d.Delete("foo.ca")
+		Expect(d.Set("foo.ca", "ca.pem")).To(Succeed())
+		Expect(d.Origin("foo.ca")).To(BeEmpty())
+	})
+
+	It("forgets origins of changed subtrees and ancestors only", func() {
+		tmp := GinkgoT().TempDir()
+		name := writeFile(tmp, "conf.yaml", `
+foo:
+  cert: cert.pem
+  key: key.pem
+bar:
+  baz: 42
+  qux: 666
+`)
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(name, yaml.Parser())).To(Succeed())
+
+		Expect(d.Load(mapProvider{"foo": map[string]any{"cert": "cert.pem"}}, nil)).To(Succeed())
+		Expect(d.Origin("foo.cert")).To(Equal(name))
+
+		other := koanf.New(".")
+		Expect(other.Set("baz", 1)).To(Succeed())
+		Expect(d.MergeAt(other, "bar")).To(Succeed())
+		Expect(d.Origin("bar.baz")).To(BeEmpty())
+		Expect(d.Origin("bar.qux")).To(Equal(name))
+
+		Expect(d.Set("foo", "flat")).To(Succeed())
+		Expect(d.Origin("foo.cert")).To(BeEmpty())
+		Expect(d.Origin("foo.key")).To(BeEmpty())
+		Expect(d.Origin("bar.qux")).To(Equal(name))
+
+		Expect(d.Set("bar.qux.deep", true)).To(Succeed())
+		Expect(d.Origin("bar.qux")).To(BeEmpty())
+
+		d.Delete("")
+		Expect(d.Origin("bar")).To(BeEmpty())
+	})
+
+	It("reports errors", func() {
+		tmp := GinkgoT().TempDir()
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(filepath.Join(tmp, "nada.yaml"), yaml.Parser())).NotTo(Succeed())
+		Expect(d.LoadFile(writeFile(tmp, "bad.yaml", "foo: [bar"), yaml.Parser())).NotTo(Succeed())
+	})
+
+})
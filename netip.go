@@ -0,0 +1,154 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// GetAddr returns the netip.Addr value of a configuration setting with the
+// given name. In contrast to [DeafAdder.GetIP], invalid addresses are always
+// reported as errors.
+func (d *DeafAdder) GetAddr(path string) (v netip.Addr, err error) {
+	return parse(d, path, d.parseAddr)
+}
+
+// GetAddrSlice returns the []netip.Addr value of a configuration setting with
+// the given name.
+func (d *DeafAdder) GetAddrSlice(path string) (v []netip.Addr, err error) {
+	return parseSlice(d, path, d.parseAddr)
+}
+
+// GetPrefix returns the netip.Prefix value of a configuration setting with the
+// given name.
+func (d *DeafAdder) GetPrefix(path string) (v netip.Prefix, err error) {
+	return parse(d, path, d.parsePrefix)
+}
+
+// GetPrefixSlice returns the []netip.Prefix value of a configuration setting
+// with the given name.
+func (d *DeafAdder) GetPrefixSlice(path string) (v []netip.Prefix, err error) {
+	return parseSlice(d, path, d.parsePrefix)
+}
+
+// GetAddrPort returns the netip.AddrPort value of a configuration setting with
+// the given name.
+func (d *DeafAdder) GetAddrPort(path string) (v netip.AddrPort, err error) {
+	return parse(d, path, d.parseAddrPort)
+}
+
+// GetAddrPortSlice returns the []netip.AddrPort value of a configuration
+// setting with the given name.
+func (d *DeafAdder) GetAddrPortSlice(path string) (v []netip.AddrPort, err error) {
+	return parseSlice(d, path, d.parseAddrPort)
+}
+
+func (d *DeafAdder) parseAddr(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return d.checkAddr(addr)
+}
+
+func (d *DeafAdder) parsePrefix(s string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(s))
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr, err := d.checkAddr(prefix.Addr())
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if addr == prefix.Addr() {
+		return prefix, nil
+	}
+	// The address got unmapped, so we need to adjust the prefix length
+	// accordingly.
+	bits := prefix.Bits() - 96
+	if bits < 0 {
+		return netip.Prefix{}, fmt.Errorf("cannot unmap IPv4-mapped prefix %s", prefix)
+	}
+	return netip.PrefixFrom(addr, bits), nil
+}
+
+func (d *DeafAdder) parseAddrPort(s string) (netip.AddrPort, error) {
+	addrport, err := netip.ParseAddrPort(strings.TrimSpace(s))
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	addr, err := d.checkAddr(addrport.Addr())
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	return netip.AddrPortFrom(addr, addrport.Port()), nil
+}
+
+// checkAddr checks the specified address against the zone and IPv4-mapped
+// address settings, returning the address unmapped if necessary.
+func (d *DeafAdder) checkAddr(addr netip.Addr) (netip.Addr, error) {
+	opts := d.settings()
+	if addr.Zone() != "" && opts.noZones {
+		return netip.Addr{}, fmt.Errorf("IP address %s must not have a zone", addr)
+	}
+	if !addr.Is4In6() {
+		return addr, nil
+	}
+	switch opts.ipv4Mapped {
+	case UnmapIPv4Mapped:
+		return addr.Unmap(), nil
+	case RejectIPv4Mapped:
+		return netip.Addr{}, fmt.Errorf("IPv4-mapped IPv6 address %s not allowed", addr)
+	}
+	return addr, nil
+}
+
+// SetAddr sets the configuration setting with the given name to the specified
+// netip.Addr value.
+func (d *DeafAdder) SetAddr(path string, v netip.Addr) error {
+	return d.set(path, v.String())
+}
+
+// SetAddrSlice sets the configuration setting with the given name to the
+// specified []netip.Addr value.
+func (d *DeafAdder) SetAddrSlice(path string, v []netip.Addr) error {
+	return setStrings(d, path, v, netip.Addr.String)
+}
+
+// SetPrefix sets the configuration setting with the given name to the
+// specified netip.Prefix value.
+func (d *DeafAdder) SetPrefix(path string, v netip.Prefix) error {
+	return d.set(path, v.String())
+}
+
+// SetPrefixSlice sets the configuration setting with the given name to the
+// specified []netip.Prefix value.
+func (d *DeafAdder) SetPrefixSlice(path string, v []netip.Prefix) error {
+	return setStrings(d, path, v, netip.Prefix.String)
+}
+
+// SetAddrPort sets the configuration setting with the given name to the
+// specified netip.AddrPort value.
+func (d *DeafAdder) SetAddrPort(path string, v netip.AddrPort) error {
+	return d.set(path, v.String())
+}
+
+// SetAddrPortSlice sets the configuration setting with the given name to the
+// specified []netip.AddrPort value.
+func (d *DeafAdder) SetAddrPortSlice(path string, v []netip.AddrPort) error {
+	return setStrings(d, path, v, netip.AddrPort.String)
+}
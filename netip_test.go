@@ -0,0 +1,114 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"net/netip"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("net/netip accessors", func() {
+
+	const s = `
+addr: 10.0.0.1
+addr-zone: fe80::1%eth0
+addr-mapped: "::ffff:10.0.0.1"
+addrs:
+  - 10.0.0.1
+  - fe80::1
+bad-addrs:
+  - 10.0.0.1
+  - 10.0.0.666
+prefix: 10.0.0.0/8
+prefix-mapped: "::ffff:10.0.0.0/104"
+prefix-mapped-short: "::ffff:0.0.0.0/64"
+prefixes:
+  - 10.0.0.0/8
+  - fe80::/10
+addrport: 10.0.0.1:80
+addrport-zone: "[fe80::1%eth0]:80"
+addrport-mapped: "[::ffff:10.0.0.1]:80"
+addrports:
+  - 10.0.0.1:80
+  - "[fe80::1]:443"
+`
+
+	loadWith := func(opts ...Option) *DeafAdder {
+		GinkgoHelper()
+		d := New(koanf.New("."), opts...)
+		Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+		return d
+	}
+
+	It("returns addresses, prefixes and address-ports", func() {
+		d := loadWith()
+		Expect(d.GetAddr("addr")).To(Equal(netip.MustParseAddr("10.0.0.1")))
+		Expect(d.GetAddr("addr-zone")).To(Equal(netip.MustParseAddr("fe80::1%eth0")))
+		Expect(d.GetAddr("addr-mapped")).To(Equal(netip.MustParseAddr("::ffff:10.0.0.1")))
+		Expect(d.GetAddrSlice("addrs")).To(Equal([]netip.Addr{
+			netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("fe80::1")}))
+
+		Expect(d.GetPrefix("prefix")).To(Equal(netip.MustParsePrefix("10.0.0.0/8")))
+		Expect(d.GetPrefixSlice("prefixes")).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fe80::/10")}))
+
+		Expect(d.GetAddrPort("addrport")).To(Equal(netip.MustParseAddrPort("10.0.0.1:80")))
+		Expect(d.GetAddrPort("addrport-zone")).To(Equal(netip.MustParseAddrPort("[fe80::1%eth0]:80")))
+		Expect(d.GetAddrPortSlice("addrports")).To(Equal([]netip.AddrPort{
+			netip.MustParseAddrPort("10.0.0.1:80"), netip.MustParseAddrPort("[fe80::1]:443")}))
+	})
+
+	It("reports invalid values", func() {
+		d := loadWith()
+		Expect(d.GetAddr("nada")).Error().To(
+			MatchError(ContainSubstring("no such configuration setting nada")))
+		Expect(d.GetAddr("prefix")).Error().To(
+			MatchError(ContainSubstring("invalid value for configuration setting prefix")))
+		Expect(d.GetAddrSlice("addr")).Error().To(
+			MatchError(ContainSubstring("value for configuration setting addr must be slice")))
+		Expect(d.GetAddrSlice("bad-addrs")).Error().To(
+			MatchError(ContainSubstring("invalid value for configuration setting bad-addrs[1]")))
+		Expect(d.GetPrefix("addr")).Error().To(HaveOccurred())
+		Expect(d.GetAddrPort("addr")).Error().To(HaveOccurred())
+	})
+
+	It("rejects zones", func() {
+		d := loadWith(WithoutIPZones())
+		Expect(d.GetAddr("addr-zone")).Error().To(MatchError(ContainSubstring("must not have a zone")))
+		Expect(d.GetAddrPort("addrport-zone")).Error().To(MatchError(ContainSubstring("must not have a zone")))
+	})
+
+	It("unmaps IPv4-mapped addresses", func() {
+		d := loadWith(WithIPv4Mapped(UnmapIPv4Mapped))
+		Expect(d.GetAddr("addr-mapped")).To(Equal(netip.MustParseAddr("10.0.0.1")))
+		Expect(d.GetPrefix("prefix-mapped")).To(Equal(netip.MustParsePrefix("10.0.0.0/8")))
+		Expect(d.GetPrefix("prefix-mapped-short")).Error().To(MatchError(ContainSubstring("cannot unmap")))
+		Expect(d.GetAddrPort("addrport-mapped")).To(Equal(netip.MustParseAddrPort("10.0.0.1:80")))
+	})
+
+	It("rejects IPv4-mapped addresses", func() {
+		d := loadWith(WithIPv4Mapped(RejectIPv4Mapped))
+		Expect(d.GetAddr("addr-mapped")).Error().To(MatchError(ContainSubstring("not allowed")))
+		Expect(d.GetPrefix("prefix-mapped")).Error().To(MatchError(ContainSubstring("not allowed")))
+		Expect(d.GetAddrPort("addrport-mapped")).Error().To(MatchError(ContainSubstring("not allowed")))
+	})
+
+})
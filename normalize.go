@@ -0,0 +1,179 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// NormalizeFunc normalizes a configuration key segment, with the same signature
+// as the normalization functions used with [pflag.FlagSet.SetNormalizeFunc],
+// so that the same normalization function can be used for CLI flags as well
+// as configuration settings.
+type NormalizeFunc func(f *pflag.FlagSet, name string) pflag.NormalizedName
+
+// WithNormalizeFunc sets the normalization function for configuration setting
+// names, using the same normalization function as for CLI flags. Lookups then
+// match configuration keys after normalizing each key segment, so that, for
+// instance, “max_conns” in a configuration file matches a lookup of
+// “max-conns”. See also [DeafAdder.Normalize] for normalizing the keys of the
+// loaded configuration itself.
+//
+// Lookups use an index of the normalized keys that gets updated whenever the
+// configuration is changed through the DeafAdder object, such as using
+// [DeafAdder.Load] or [DeafAdder.Set], but not when changing the wrapped
+// koanf.Koanf object directly.
+func WithNormalizeFunc(fn NormalizeFunc) Option {
+	return func(d *DeafAdder) {
+		d.opts.normalize = fn
+	}
+}
+
+// NormalizeDashes is a [NormalizeFunc] that lower-cases names and
+// replaces underscores with dashes.
+func NormalizeDashes(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	return pflag.NormalizedName(strings.ReplaceAll(strings.ToLower(name), "_", "-"))
+}
+
+// normalizeFlagSet is passed to normalization functions, as they expect a
+// flag set.
+var normalizeFlagSet = pflag.NewFlagSet("deafadder-normalize", pflag.ContinueOnError)
+
+// normalizePath normalizes each segment of the specified path.
+func (d *DeafAdder) normalizePath(path string) string {
+	fn := d.settings().normalize
+	delim := d.Delim()
+	segments := strings.Split(path, delim)
+	for idx, segment := range segments {
+		segments[idx] = string(fn(normalizeFlagSet, segment))
+	}
+	return strings.Join(segments, delim)
+}
+
+// get returns the value for the specified path, matching the path after
+// normalization if enabled. It returns nil if there is no such value, and an
+// error if multiple configuration settings match after normalization, even if
+// one of them matches exactly.
+func (d *DeafAdder) get(path string) (any, error) {
+	value := d.Get(path)
+	if d.settings().normalize == nil {
+		return value, nil
+	}
+	matches := d.normalizedKeys()[d.normalizePath(path)]
+	switch {
+	case len(matches) > 1:
+		matches = slices.Sorted(slices.Values(matches))
+		return nil, fmt.Errorf("configuration settings %s collide after normalization",
+			strings.Join(matches, ", "))
+	case value != nil || len(matches) == 0:
+		return value, nil
+	}
+	return d.Get(matches[0]), nil
+}
+
+// normalizedKeys returns the configuration keys indexed by their normalized
+// forms, building the index only when the configuration has changed since, see
+// also track.
+func (d *DeafAdder) normalizedKeys() map[string][]string {
+	d.indexMu.Lock()
+	defer d.indexMu.Unlock()
+	if d.index == nil {
+		d.index = map[string][]string{}
+		for key := range d.KeyMap() {
+			normalized := d.normalizePath(key)
+			d.index[normalized] = append(d.index[normalized], key)
+		}
+	}
+	return d.index
+}
+
+// Normalize normalizes all keys of the loaded configuration using the
+// normalization function set with [WithNormalizeFunc]. If keys collide after
+// normalization, such as “max_conns” and “max-conns”, Normalize leaves the
+// configuration untouched and reports all collisions instead. Normalize also
+// normalizes the keys of maps inside lists.
+func (d *DeafAdder) Normalize() error {
+	fn := d.settings().normalize
+	if fn == nil {
+		return errors.New("no normalization function set")
+	}
+	var collisions []error
+	normalized := d.normalizeMap(d.Raw(), nil, &collisions)
+	if len(collisions) > 0 {
+		return errors.Join(collisions...)
+	}
+	o := d.settings()
+	o.mu.Lock()
+	origins := make(map[string]string, len(o.origins))
+	for key, origin := range o.origins {
+		origins[d.normalizePath(key)] = origin
+	}
+	o.origins = nil
+	o.mu.Unlock()
+	d.Delete("")
+	return d.load(normalized, origins)
+}
+
+// normalizeMap returns a copy of the specified configuration map with all keys
+// normalized, recording key collisions.
+func (d *DeafAdder) normalizeMap(mp map[string]any, path []string, collisions *[]error) map[string]any {
+	fn := d.settings().normalize
+	keys := make([]string, 0, len(mp))
+	for key := range mp {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	out := make(map[string]any, len(mp))
+	originals := map[string]string{}
+	for _, key := range keys {
+		nkey := string(fn(normalizeFlagSet, key))
+		if original, ok := originals[nkey]; ok {
+			prefix := strings.Join(path, d.Delim())
+			if prefix != "" {
+				prefix += d.Delim()
+			}
+			*collisions = append(*collisions, fmt.Errorf(
+				"configuration settings %s%s and %s%s collide after normalization",
+				prefix, original, prefix, key))
+			continue
+		}
+		originals[nkey] = key
+		out[nkey] = d.normalizeValue(mp[key], append(slices.Clone(path), nkey), collisions)
+	}
+	return out
+}
+
+// normalizeValue returns the specified configuration value, with all keys of
+// maps normalized, including maps inside lists.
+func (d *DeafAdder) normalizeValue(value any, path []string, collisions *[]error) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return d.normalizeMap(v, path, collisions)
+	case []any:
+		elements := make([]any, len(v))
+		for idx, element := range v {
+			elementPath := slices.Clone(path)
+			elementPath[len(elementPath)-1] += fmt.Sprintf("[%d]", idx)
+			elements[idx] = d.normalizeValue(element, elementPath, collisions)
+		}
+		return elements
+	}
+	return value
+}
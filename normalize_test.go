@@ -0,0 +1,150 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("key normalization", func() {
+
+	loadWith := func(s string, opts ...Option) *DeafAdder {
+		GinkgoHelper()
+		d := New(koanf.New("."), opts...)
+		Expect(d.Load(rawbytes.Provider([]byte(s)), yaml.Parser())).To(Succeed())
+		return d
+	}
+
+	It("is compatible with pflag", func() {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.SetNormalizeFunc(NormalizeDashes)
+		fs.Int("max-conns", 0, "maximum connections")
+		Expect(fs.Parse([]string{"--Max_Conns=42"})).To(Succeed())
+		Expect(fs.GetInt("max-conns")).To(Equal(42))
+	})
+
+	It("matches normalized keys on lookup", func() {
+		const s = `
+Server:
+  max_conns: 42
+  max-idle: 10
+`
+		Expect(loadWith(s).GetInt("server.max-conns")).Error().To(HaveOccurred())
+
+		d := loadWith(s, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.GetInt("server.max-conns")).To(Equal(42))
+		Expect(d.GetInt("SERVER.MAX_IDLE")).To(Equal(10))
+		Expect(d.GetInt("server.nada")).Error().To(MatchError(ContainSubstring(
+			"no such configuration setting server.nada")))
+	})
+
+	It("reports colliding keys on lookup", func() {
+		d := loadWith(`
+max_conns: 42
+max-Conns: 666
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.GetInt("MAX-CONNS")).Error().To(MatchError(
+			"configuration settings max-Conns, max_conns collide after normalization"))
+
+		d = loadWith(`
+max-conns: 42
+max_conns: 666
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.GetInt("max-conns")).Error().To(MatchError(
+			"configuration settings max-conns, max_conns collide after normalization"))
+	})
+
+	It("sees configuration changes on lookup", func() {
+		d := loadWith(`
+max_conns: 42
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.GetInt("max-conns")).To(Equal(42))
+		Expect(d.GetInt("max-idle")).Error().To(HaveOccurred())
+
+		Expect(d.Load(mapProvider{"Max_Idle": 10}, nil)).To(Succeed())
+		Expect(d.GetInt("max-idle")).To(Equal(10))
+
+		Expect(d.Set("Max-Conns", 666)).To(Succeed())
+		Expect(d.GetInt("max-conns")).Error().To(MatchError(
+			"configuration settings Max-Conns, max_conns collide after normalization"))
+
+		d.Delete("max_conns")
+		Expect(d.GetInt("max-conns")).To(Equal(666))
+	})
+
+	It("normalizes the loaded configuration", func() {
+		Expect(New(koanf.New(".")).Normalize()).To(MatchError("no normalization function set"))
+
+		d := loadWith(`
+Server:
+  max_conns: 42
+  Listen_Addrs: [":80", ":443"]
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.Normalize()).To(Succeed())
+		Expect(d.Keys()).To(ConsistOf("server.max-conns", "server.listen-addrs"))
+		Expect(d.GetInt("server.max-conns")).To(Equal(42))
+
+		d = loadWith(`
+server:
+  max_conns: 42
+  max-conns: 666
+  Max-Conns: 0
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.Normalize()).To(MatchError(And(
+			ContainSubstring("configuration settings server.Max-Conns and server.max-conns collide"),
+			ContainSubstring("configuration settings server.Max-Conns and server.max_conns collide"))))
+		Expect(d.Keys()).To(HaveLen(3))
+	})
+
+	It("normalizes maps inside lists", func() {
+		d := loadWith(`
+Listeners:
+  - Bind_Addr: ":80"
+  - - Bind_Addr: ":443"
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.Normalize()).To(Succeed())
+		Expect(d.Get("listeners")).To(Equal([]any{
+			map[string]any{"bind-addr": ":80"},
+			[]any{map[string]any{"bind-addr": ":443"}},
+		}))
+
+		d = loadWith(`
+listeners:
+  - bind_addr: ":80"
+    Bind-Addr: ":443"
+`, WithNormalizeFunc(NormalizeDashes))
+		Expect(d.Normalize()).To(MatchError(
+			"configuration settings listeners[0].Bind-Addr and listeners[0].bind_addr collide after normalization"))
+	})
+
+	It("keeps origins when normalizing", func() {
+		tmp := GinkgoT().TempDir()
+		config := writeFile(tmp, "config.yaml", `
+TLS:
+  cert_file: cert.pem
+`)
+		d := New(koanf.New("."), WithNormalizeFunc(NormalizeDashes))
+		Expect(d.LoadFile(config, yaml.Parser())).To(Succeed())
+		Expect(d.Normalize()).To(Succeed())
+		Expect(d.Origin("tls.cert-file")).To(Equal(config))
+	})
+
+})
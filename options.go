@@ -0,0 +1,107 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// Option configures a DeafAdder object when creating it using [New].
+type Option func(*DeafAdder)
+
+// options are the settings of a DeafAdder object.
+type options struct {
+	ipv4Mapped IPv4Mapped
+	noZones    bool
+	strictIP   bool
+
+	interpolate bool
+	includeKey  string
+	profilesKey string
+
+	aliases   []alias
+	logger    *slog.Logger
+	normalize NormalizeFunc
+
+	baseDir string
+
+	regexps sync.Map // compiled regular expressions, by pattern
+
+	resolvers map[string]SecretResolver // secret resolvers, by scheme
+
+	mu      sync.RWMutex
+	origins map[string]string // configuration file names, by setting name
+	secrets map[string]bool   // setting names of secrets
+	warned  map[string]bool   // deprecated setting names already warned about
+}
+
+// settings returns the settings of this DeafAdder object, lazily falling back
+// to the default settings in case this DeafAdder object wasn't created using
+// [New].
+func (d *DeafAdder) settings() *options {
+	d.once.Do(func() {
+		if d.opts == nil {
+			d.opts = &options{}
+		}
+	})
+	return d.opts
+}
+
+// IPv4Mapped specifies how the net/netip accessors handle IPv4-mapped IPv6
+// addresses, such as “::ffff:10.0.0.1”.
+type IPv4Mapped int
+
+const (
+	AllowIPv4Mapped  IPv4Mapped = iota // accept and return as-is (default)
+	UnmapIPv4Mapped                    // accept and unmap into IPv4 addresses
+	RejectIPv4Mapped                   // reject as invalid
+)
+
+// WithIPv4Mapped sets how the net/netip accessors, such as
+// [DeafAdder.GetAddr], handle IPv4-mapped IPv6 addresses.
+func WithIPv4Mapped(policy IPv4Mapped) Option {
+	return func(d *DeafAdder) {
+		d.opts.ipv4Mapped = policy
+	}
+}
+
+// WithoutIPZones rejects IPv6 addresses with zones, such as “fe80::1%eth0”, in
+// the net/netip accessors, such as [DeafAdder.GetAddr].
+func WithoutIPZones() Option {
+	return func(d *DeafAdder) {
+		d.opts.noZones = true
+	}
+}
+
+// WithStrictIP enables strict conversion of IP addresses, IP networks, and IP
+// masks in [DeafAdder.GetIP], [DeafAdder.GetIPSlice], [DeafAdder.GetIPNet],
+// [DeafAdder.GetIPNetSlice], and [DeafAdder.GetIPMask]. In strict mode, empty
+// addresses, CIDRs with host bits set, and non-canonical masks are reported as
+// [*ConversionError] instead of being silently accepted.
+func WithStrictIP() Option {
+	return func(d *DeafAdder) {
+		d.opts.strictIP = true
+	}
+}
+
+// WithBaseDir sets the base directory to resolve relative paths against in
+// [DeafAdder.GetPath] and [DeafAdder.GetPathSlice] when the configuration file
+// that supplied a path is unknown.
+func WithBaseDir(dir string) Option {
+	return func(d *DeafAdder) {
+		d.opts.baseDir = dir
+	}
+}
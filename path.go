@@ -0,0 +1,124 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathCheck checks a resolved filesystem path, returning an error if the path
+// doesn't pass the check.
+type PathCheck func(path string) error
+
+// MustExist returns a [PathCheck] that requires paths to exist.
+func MustExist() PathCheck {
+	return func(path string) error {
+		_, err := os.Stat(path)
+		return err
+	}
+}
+
+// MustBeDir returns a [PathCheck] that requires paths to be directories.
+func MustBeDir() PathCheck {
+	return func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+		return nil
+	}
+}
+
+// MustBeFile returns a [PathCheck] that requires paths to be regular files.
+func MustBeFile() PathCheck {
+	return func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+		return nil
+	}
+}
+
+// MustBeReadable returns a [PathCheck] that requires paths to be readable by
+// this process.
+func MustBeReadable() PathCheck {
+	return func(path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+}
+
+// GetPath returns the filesystem path value of a configuration setting with
+// the given name, additionally passing the path through the optionally
+// specified checks. GetPath first expands environment variables, such as
+// “$HOME”, and a leading “~”. Relative paths are then resolved against the
+// directory of the configuration file that supplied the setting, see
+// [DeafAdder.LoadFile]. If there is no such file, relative paths are resolved
+// against the base directory set using [WithBaseDir], if any.
+func (d *DeafAdder) GetPath(path string, checks ...PathCheck) (v string, err error) {
+	return parse(d, path, d.pathResolver(path, checks))
+}
+
+// GetPathSlice returns the []string filesystem paths value of a configuration
+// setting with the given name, resolving and checking each path as described
+// in [DeafAdder.GetPath].
+func (d *DeafAdder) GetPathSlice(path string, checks ...PathCheck) (v []string, err error) {
+	return parseSlice(d, path, d.pathResolver(path, checks))
+}
+
+// pathResolver returns a parse function resolving filesystem paths supplied
+// by the configuration setting with the given name.
+func (d *DeafAdder) pathResolver(path string, checks []PathCheck) func(string) (string, error) {
+	return func(s string) (string, error) {
+		p := os.ExpandEnv(s)
+		if p == "~" || strings.HasPrefix(p, "~"+string(filepath.Separator)) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			p = filepath.Join(home, p[1:])
+		}
+		if p == "" {
+			return "", fmt.Errorf("empty path %q", s)
+		}
+		if !filepath.IsAbs(p) {
+			base := d.settings().baseDir
+			if origin := d.Origin(path); origin != "" {
+				base = filepath.Dir(origin)
+			}
+			p = filepath.Join(base, p)
+		}
+		p = filepath.Clean(p)
+		for _, check := range checks {
+			if err := check(p); err != nil {
+				return "", err
+			}
+		}
+		return p, nil
+	}
+}
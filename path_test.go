@@ -0,0 +1,83 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("path accessors", func() {
+
+	It("resolves paths relative to their configuration files", func() {
+		tmp := GinkgoT().TempDir()
+		cert := writeFile(tmp, "etc/app/certs/server.pem", "")
+		writeFile(tmp, "etc/app/certs/ca.pem", "")
+		conf := writeFile(tmp, "etc/app/config.yaml", `
+tls:
+  cert-file: certs/server.pem
+  certs-dir: ./certs/
+  cas:
+    - certs/server.pem
+    - certs/ca.pem
+    - /etc/ssl/nada.pem
+  absolute: /etc/ssl
+  home: ~/.config/app
+  env: $DEAFADDER_TEST_DIR/foo
+`)
+		GinkgoT().Setenv("DEAFADDER_TEST_DIR", "/var/lib/app")
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(conf, yaml.Parser())).To(Succeed())
+
+		Expect(d.GetPath("tls.cert-file", MustExist(), MustBeFile(), MustBeReadable())).To(Equal(cert))
+		Expect(d.GetPath("tls.certs-dir", MustBeDir())).To(Equal(filepath.Join(tmp, "etc/app/certs")))
+		Expect(d.GetPath("tls.absolute")).To(Equal("/etc/ssl"))
+		home := Successful(os.UserHomeDir())
+		Expect(d.GetPath("tls.home")).To(Equal(filepath.Join(home, ".config/app")))
+		Expect(d.GetPath("tls.env")).To(Equal("/var/lib/app/foo"))
+
+		Expect(d.GetPathSlice("tls.cas")).To(Equal([]string{
+			cert,
+			filepath.Join(tmp, "etc/app/certs/ca.pem"),
+			"/etc/ssl/nada.pem",
+		}))
+
+		Expect(d.GetPath("tls.cert-file", MustBeDir())).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting tls.cert-file")))
+		Expect(d.GetPath("tls.certs-dir", MustBeFile())).Error().To(MatchError(ContainSubstring(
+			"is not a regular file")))
+		Expect(d.GetPathSlice("tls.cas", MustExist())).Error().To(MatchError(ContainSubstring(
+			"tls.cas[2]")))
+	})
+
+	It("resolves paths relative to a base directory", func() {
+		d := New(koanf.New("."), WithBaseDir("/etc/app"))
+		Expect(d.Load(rawbytes.Provider([]byte(`
+cert-file: certs/server.pem
+empty: ""
+`)), yaml.Parser())).To(Succeed())
+		Expect(d.GetPath("cert-file")).To(Equal("/etc/app/certs/server.pem"))
+		Expect(d.GetPath("empty")).Error().To(MatchError(ContainSubstring("empty path")))
+	})
+
+})
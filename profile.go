@@ -0,0 +1,156 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// WithProfilesKey sets the key of the configuration map holding the profiles,
+// defaulting to “profiles”.
+func WithProfilesKey(key string) Option {
+	return func(d *DeafAdder) {
+		d.opts.profilesKey = key
+	}
+}
+
+// ProfileSource returns the names of the active profiles, or nil if it doesn't
+// know about any active profiles.
+type ProfileSource func(d *DeafAdder) []string
+
+// ProfilesFromFlag returns a [ProfileSource] taking the active profiles from
+// the flag with the given name, but only if the flag was set. The flag can be
+// a string flag with a comma-separated list of profile names, or a string
+// slice flag.
+func ProfilesFromFlag(fs *pflag.FlagSet, name string) ProfileSource {
+	return func(*DeafAdder) []string {
+		flag := fs.Lookup(name)
+		if flag == nil || !flag.Changed {
+			return nil
+		}
+		if sv, ok := flag.Value.(pflag.SliceValue); ok {
+			return sv.GetSlice()
+		}
+		return splitProfiles(flag.Value.String())
+	}
+}
+
+// ProfilesFromEnv returns a [ProfileSource] taking the active profiles from the
+// environment variable with the given name, containing a comma-separated list
+// of profile names.
+func ProfilesFromEnv(name string) ProfileSource {
+	return func(*DeafAdder) []string {
+		return splitProfiles(os.Getenv(name))
+	}
+}
+
+// ProfilesFromKey returns a [ProfileSource] taking the active profiles from the
+// configuration setting with the given name, containing either a
+// comma-separated list of profile names or a list of profile names.
+func ProfilesFromKey(path string) ProfileSource {
+	return func(d *DeafAdder) []string {
+		switch value := d.Get(path).(type) {
+		case nil:
+			return nil
+		case []any:
+			names := make([]string, 0, len(value))
+			for _, name := range value {
+				names = append(names, fmt.Sprintf("%v", name))
+			}
+			return names
+		default:
+			return splitProfiles(fmt.Sprintf("%v", value))
+		}
+	}
+}
+
+// splitProfiles splits a comma-separated list of profile names, ignoring empty
+// names.
+func splitProfiles(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ActiveProfiles returns the names of the active profiles as returned by the
+// first of the specified sources knowing about active profiles, in the order
+// given. For instance, a CLI flag can override an environment variable, which
+// in turn overrides a configuration setting:
+//
+//	names := d.ActiveProfiles(
+//	    deafadder.ProfilesFromFlag(cmd.Flags(), "profile"),
+//	    deafadder.ProfilesFromEnv("APP_PROFILE"),
+//	    deafadder.ProfilesFromKey("profile"))
+func (d *DeafAdder) ActiveProfiles(sources ...ProfileSource) []string {
+	for _, source := range sources {
+		if names := source(d); len(names) > 0 {
+			return names
+		}
+	}
+	return nil
+}
+
+// ApplyProfiles overlays the configuration subtrees of the named profiles onto
+// the base configuration, in the order given, so that later profiles take
+// precedence over earlier profiles. For instance, given
+//
+//	log:
+//	  level: info
+//	profiles:
+//	  dev:
+//	    log:
+//	      level: debug
+//
+// applying the “dev” profile sets “log.level” to “debug”. The profiles are
+// taken from the “profiles” key, unless set otherwise using [WithProfilesKey].
+// Applying an unknown profile is reported as an error, leaving the
+// configuration unchanged.
+func (d *DeafAdder) ApplyProfiles(names ...string) error {
+	key := d.settings().profilesKey
+	if key == "" {
+		key = "profiles"
+	}
+	delim := d.Delim()
+	profiles := make([]map[string]any, len(names))
+	for idx, name := range names {
+		profile, ok := d.Get(key + delim + name).(map[string]any)
+		if !ok {
+			return fmt.Errorf("no such profile %s", name)
+		}
+		profiles[idx] = profile
+	}
+	for idx, name := range names {
+		path := key + delim + name
+		profile := profiles[idx]
+		origins := map[string]string{}
+		for _, key := range d.Cut(path).Keys() {
+			if origin := d.Origin(path + delim + key); origin != "" {
+				origins[key] = origin
+			}
+		}
+		if err := d.load(profile, origins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("profiles", func() {
+
+	const s = `
+profile: dev
+log:
+  level: info
+  format: text
+db:
+  host: localhost
+profiles:
+  dev:
+    log:
+      level: debug
+  prod:
+    log:
+      format: json
+    db:
+      host: db.example.org
+`
+
+	It("determines active profiles", func() {
+		d := load(s)
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.String("profile", "", "active profiles")
+		fs.StringSlice("profiles", nil, "active profiles")
+		sources := []ProfileSource{
+			ProfilesFromFlag(fs, "profile"),
+			ProfilesFromFlag(fs, "profiles"),
+			ProfilesFromFlag(fs, "nada"),
+			ProfilesFromEnv("DEAFADDER_TEST_PROFILES"),
+			ProfilesFromKey("profile"),
+		}
+		Expect(d.ActiveProfiles(sources...)).To(Equal([]string{"dev"}))
+		GinkgoT().Setenv("DEAFADDER_TEST_PROFILES", "prod, ,dev")
+		Expect(d.ActiveProfiles(sources...)).To(Equal([]string{"prod", "dev"}))
+		Expect(fs.Parse([]string{"--profiles=a,b"})).To(Succeed())
+		Expect(d.ActiveProfiles(sources...)).To(Equal([]string{"a", "b"}))
+		Expect(fs.Parse([]string{"--profile=c,d"})).To(Succeed())
+		Expect(d.ActiveProfiles(sources...)).To(Equal([]string{"c", "d"}))
+
+		Expect(load(`profile: [x, y]`).ActiveProfiles(ProfilesFromKey("profile"))).To(
+			Equal([]string{"x", "y"}))
+		Expect(d.ActiveProfiles(ProfilesFromKey("nada"))).To(BeNil())
+	})
+
+	It("overlays profiles", func() {
+		d := load(s)
+		Expect(d.ApplyProfiles("prod", "dev")).To(Succeed())
+		Expect(d.GetString("log.level")).To(Equal("debug"))
+		Expect(d.GetString("log.format")).To(Equal("json"))
+		Expect(d.GetString("db.host")).To(Equal("db.example.org"))
+		Expect(d.ApplyProfiles("staging")).To(MatchError("no such profile staging"))
+	})
+
+	It("applies either all or no profiles", func() {
+		d := load(s)
+		before := d.All()
+		Expect(d.ApplyProfiles("prod", "nope")).To(MatchError("no such profile nope"))
+		Expect(d.All()).To(Equal(before))
+	})
+
+	It("uses a custom profiles key and keeps origins", func() {
+		tmp := GinkgoT().TempDir()
+		config := writeFile(tmp, "config.yaml", `
+tls:
+  cert: default.pem
+envs:
+  prod:
+    tls:
+      cert: prod.pem
+`)
+		d := New(koanf.New("."), WithProfilesKey("envs"))
+		Expect(d.LoadFile(config, yaml.Parser())).To(Succeed())
+		Expect(d.ApplyProfiles("prod")).To(Succeed())
+		Expect(d.GetString("tls.cert")).To(Equal("prod.pem"))
+		Expect(d.Origin("tls.cert")).To(Equal(config))
+	})
+
+})
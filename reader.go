@@ -0,0 +1,112 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Reader reads multiple configuration settings into variables, collecting all
+// errors instead of stopping at the first error. This allows reporting all
+// missing and malformed configuration settings at once.
+//
+//	var port int
+//	var timeout time.Duration
+//	err := d.Reader().
+//		Read("server.port", &port).
+//		Optional("server.timeout", &timeout).
+//		Err()
+type Reader struct {
+	d    *DeafAdder
+	errs []error
+}
+
+// Reader returns a new [Reader] for reading the configuration settings of this
+// DeafAdder object.
+func (d *DeafAdder) Reader() *Reader {
+	return &Reader{d: d}
+}
+
+// Read reads the value of the configuration setting with the given name into
+// the variable p points to, using the accessor for p's element type as [Get]
+// does. In case of an error, Read leaves the variable untouched and records
+// the error.
+func (r *Reader) Read(path string, p any) *Reader {
+	pv := reflect.ValueOf(p)
+	if pv.Kind() != reflect.Pointer || pv.IsNil() {
+		r.errs = append(r.errs, fmt.Errorf(
+			"destination for configuration setting %s must be non-nil pointer", r.d.abs(path)))
+		return r
+	}
+	get, err := getter(pv.Type().Elem())
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("configuration setting %s: %w", r.d.abs(path), err))
+		return r
+	}
+	v, err := get(r.d, path)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return r
+	}
+	if v != nil {
+		pv.Elem().Set(reflect.ValueOf(v))
+	}
+	return r
+}
+
+// Optional reads the value of the configuration setting with the given name
+// into the variable p points to, like [Reader.Read] does, but only if the
+// configuration setting exists; otherwise, the variable keeps its value.
+func (r *Reader) Optional(path string, p any) *Reader {
+	if !r.d.exists(path) {
+		return r
+	}
+	return r.Read(path, p)
+}
+
+// ReadWith reads the value of the configuration setting with the given name
+// into the variable p points to, using the specified typed accessor, such as
+// (*DeafAdder).GetCount. ReadWith is the generic counterpart to [Reader.Read]
+// for accessors requiring additional parameters or not used by [Get].
+//
+//	deafadder.ReadWith(r, "log.format", &format, func(d *deafadder.DeafAdder, path string) (string, error) {
+//		return d.GetEnum(path, formats)
+//	})
+func ReadWith[T any](r *Reader, path string, p *T, get func(*DeafAdder, string) (T, error)) *Reader {
+	v, err := get(r.d, path)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return r
+	}
+	*p = v
+	return r
+}
+
+// Err returns all errors recorded so far, joined using [errors.Join], or nil if
+// there were no errors.
+func (r *Reader) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// exists returns true if there is a value for the configuration setting with
+// the given name, taking deprecated aliases and normalization into account.
+// In case of conflicts or collisions, exists returns true in order to make
+// them visible.
+func (d *DeafAdder) exists(path string) bool {
+	value, err := d.top().resolve(d.abs(path))
+	return err != nil || value != nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("batch reading", func() {
+
+	const conf = `
+server:
+  port: 8080
+  timeout: 5s
+  retries: many
+  verbosity: 3
+`
+
+	It("reads all settings", func() {
+		d := load(conf)
+		var port int
+		var timeout time.Duration
+		var verbosity int
+		backoff := time.Second
+		Expect(d.Reader().
+			Read("server.port", &port).
+			Read("server.timeout", &timeout).
+			Optional("server.backoff", &backoff).
+			Optional("server.timeout", &backoff).
+			Err()).To(Succeed())
+		Expect(port).To(Equal(8080))
+		Expect(timeout).To(Equal(5 * time.Second))
+		Expect(backoff).To(Equal(5 * time.Second))
+
+		server := d.Sub("server")
+		Expect(ReadWith(server.Reader(), "verbosity", &verbosity, (*DeafAdder).GetCount).
+			Err()).To(Succeed())
+		Expect(verbosity).To(Equal(3))
+	})
+
+	It("reports all errors", func() {
+		d := load(conf)
+		retries := 42
+		var port uint8
+		var nada chan int
+		var missing string
+		err := d.Sub("server").Reader().
+			Read("retries", &retries).
+			Read("port", &port).
+			Read("missing", &missing).
+			Read("port", nada).
+			Read("port", &nada).
+			Err()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(And(
+			ContainSubstring("configuration setting server.retries"),
+			ContainSubstring("configuration setting server.port"),
+			ContainSubstring("no such configuration setting server.missing"),
+			ContainSubstring("destination for configuration setting server.port must be non-nil pointer"),
+			ContainSubstring("configuration setting server.port: no flag value registered for type chan int"),
+		))
+		Expect(retries).To(Equal(42))
+	})
+
+})
@@ -0,0 +1,58 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import "regexp"
+
+// GetRegexp returns the compiled *regexp.Regexp value of a configuration
+// setting with the given name. Regular expressions are compiled only once per
+// DeafAdder object and pattern, and then reused, as *regexp.Regexp objects are
+// safe for concurrent use.
+func (d *DeafAdder) GetRegexp(path string) (v *regexp.Regexp, err error) {
+	return parse(d, path, d.compile)
+}
+
+// GetRegexpSlice returns the compiled []*regexp.Regexp value of a
+// configuration setting with the given name.
+func (d *DeafAdder) GetRegexpSlice(path string) (v []*regexp.Regexp, err error) {
+	return parseSlice(d, path, d.compile)
+}
+
+// compile the specified regular expression pattern, or return the already
+// compiled regular expression for it.
+func (d *DeafAdder) compile(pattern string) (*regexp.Regexp, error) {
+	opts := d.settings()
+	if re, ok := opts.regexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := opts.regexps.LoadOrStore(pattern, re)
+	return actual.(*regexp.Regexp), nil
+}
+
+// SetRegexp sets the configuration setting with the given name to the pattern
+// of the specified *regexp.Regexp.
+func (d *DeafAdder) SetRegexp(path string, v *regexp.Regexp) error {
+	return d.set(path, v.String())
+}
+
+// SetRegexpSlice sets the configuration setting with the given name to the
+// patterns of the specified []*regexp.Regexp value.
+func (d *DeafAdder) SetRegexpSlice(path string, v []*regexp.Regexp) error {
+	return setStrings(d, path, v, (*regexp.Regexp).String)
+}
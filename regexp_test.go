@@ -0,0 +1,49 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("regexp accessors", func() {
+
+	const s = `
+filter: '^foo-\d+$'
+filters:
+  - '^foo'
+  - 'bar$'
+bad: '(foo'
+`
+
+	It("returns compiled regular expressions only once", func() {
+		d := load(s)
+		re := Successful(d.GetRegexp("filter"))
+		Expect(re.MatchString("foo-42")).To(BeTrue())
+		Expect(d.GetRegexp("filter")).To(BeIdenticalTo(re))
+		res := Successful(d.GetRegexpSlice("filters"))
+		Expect(res).To(HaveLen(2))
+		Expect(res[1].MatchString("foobar")).To(BeTrue())
+	})
+
+	It("reports invalid regular expressions", func() {
+		d := load(s)
+		Expect(d.GetRegexp("bad")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad: error parsing regexp")))
+	})
+
+})
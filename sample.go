@@ -0,0 +1,400 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/thediveo/deafadder/sub"
+	"gopkg.in/yaml.v3"
+)
+
+// SampleFormat specifies the output format of [Sample].
+type SampleFormat int
+
+const (
+	SampleYAML SampleFormat = iota // nested YAML, usage as comments
+	SampleTOML                     // TOML tables, usage as comments
+	SampleJSON                     // nested JSON, without usage
+)
+
+// Sample writes a sample configuration in the specified format to w, with a
+// configuration setting for each flag in the specified flag set. The router
+// maps flag names to configuration setting paths, such as [sub.Prefixes];
+// flags without a route as well as a nil router put the settings at the root.
+// The settings' values are the flags' defaults in forms the typed accessors
+// accept, with the flags' usage texts as comments, except for JSON. Settings
+// for flags without default values, such as IP flags with nil defaults, are
+// commented out, and omitted from JSON. Hidden and deprecated flags are
+// skipped.
+func Sample(w io.Writer, fs *pflag.FlagSet, router sub.Router, format SampleFormat) error {
+	root := &sampleNode{}
+	var errs []error
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || flag.Deprecated != "" {
+			return
+		}
+		var path []string
+		if router != nil {
+			path = router(flag.Name)
+		}
+		if len(path) == 0 {
+			path = []string{flag.Name}
+		}
+		if err := root.add(path, flag); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	root.sort()
+	switch format {
+	case SampleYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(root.yaml()); err != nil {
+			return err
+		}
+		return enc.Close()
+	case SampleTOML:
+		return root.toml(w, nil)
+	case SampleJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root.json())
+	}
+	return fmt.Errorf("unknown sample format %d", format)
+}
+
+// sampleNode is either a configuration setting with its default value(s) and
+// usage, or a map of configuration settings.
+type sampleNode struct {
+	name     string
+	usage    string
+	value    any  // string or []string; nil for maps
+	literal  bool // value(s) are numbers or bools
+	unset    bool // setting without default value, commented out
+	children []*sampleNode
+}
+
+// isMap returns true if this node is a map of configuration settings.
+func (n *sampleNode) isMap() bool {
+	return n.value == nil && !n.unset
+}
+
+// add adds a configuration setting for the specified flag at the specified
+// path.
+func (n *sampleNode) add(path []string, flag *pflag.Flag) error {
+	for idx, name := range path[:len(path)-1] {
+		child := n.child(name)
+		if child == nil {
+			child = &sampleNode{name: name}
+			n.children = append(n.children, child)
+		} else if !child.isMap() {
+			return fmt.Errorf("flag %s conflicts with flag for configuration setting %s",
+				flag.Name, strings.Join(path[:idx+1], "."))
+		}
+		n = child
+	}
+	name := path[len(path)-1]
+	if n.child(name) != nil {
+		return fmt.Errorf("flag %s conflicts with configuration setting %s",
+			flag.Name, strings.Join(path, "."))
+	}
+	value, err := defaultValue(flag)
+	if err != nil {
+		return fmt.Errorf("invalid default value of flag %s: %w", flag.Name, err)
+	}
+	n.children = append(n.children, &sampleNode{
+		name:    name,
+		usage:   flag.Usage,
+		value:   value,
+		literal: literalTypes.MatchString(flag.Value.Type()),
+		unset:   value == nil,
+	})
+	return nil
+}
+
+// child returns the child node with the specified name, or nil.
+func (n *sampleNode) child(name string) *sampleNode {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// sort the children of this node and its descendants by their names.
+func (n *sampleNode) sort() {
+	slices.SortFunc(n.children, func(a, b *sampleNode) int {
+		return strings.Compare(a.name, b.name)
+	})
+	for _, child := range n.children {
+		child.sort()
+	}
+}
+
+// literalTypes matches the pflag value types with numbers or bools as values.
+var literalTypes = regexp.MustCompile(`^(bool|count|u?int(8|16|32|64)?|float(32|64))(Slice)?$`)
+
+// defaultValue returns the default value of the specified flag: a string for
+// scalar flags, a []string for slice flags, and nil for IP-related flags
+// without default, which pflag renders as “<nil>”. Please note that pflag
+// renders the elements of float slices with only six decimal places.
+func defaultValue(flag *pflag.Flag) (any, error) {
+	switch flag.Value.Type() {
+	case "ip", "ipMask", "ipNet":
+		if flag.DefValue == "<nil>" {
+			return nil, nil
+		}
+	}
+	sv, isSlice := flag.Value.(pflag.SliceValue)
+	if isSlice && !flag.Changed {
+		return sv.GetSlice(), nil
+	}
+	if !isSlice && !strings.HasSuffix(flag.Value.Type(), "Slice") &&
+		!strings.HasSuffix(flag.Value.Type(), "Array") {
+		return flag.DefValue, nil
+	}
+	s := strings.TrimSuffix(strings.TrimPrefix(flag.DefValue, "["), "]")
+	if s == "" {
+		return []string{}, nil
+	}
+	return csv.NewReader(strings.NewReader(s)).Read()
+}
+
+// yaml returns the YAML node representation of this node.
+func (n *sampleNode) yaml() *yaml.Node {
+	scalar := func(s string) *yaml.Node {
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: s}
+		if !n.literal || !json.Valid([]byte(s)) {
+			_ = node.Encode(s)
+		}
+		return node
+	}
+	switch value := n.value.(type) {
+	case string:
+		return scalar(value)
+	case []string:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, element := range value {
+			node.Content = append(node.Content, scalar(element))
+		}
+		return node
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	var unset []string // commented-out settings without default values
+	for _, child := range n.children {
+		if child.unset {
+			unset = append(append(unset, yamlComment(child.usage)...), "# "+child.name+":")
+			continue
+		}
+		value := child.yaml()
+		if value.Kind == yaml.MappingNode && len(value.Content) == 0 && value.FootComment != "" {
+			unset = append(unset, "# "+child.name+":")
+			for _, line := range strings.Split(value.FootComment, "\n") {
+				unset = append(unset, "#   "+line)
+			}
+			continue
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{
+				Kind:        yaml.ScalarNode,
+				Value:       child.name,
+				HeadComment: strings.Join(append(unset, yamlComment(child.usage)...), "\n"),
+			},
+			value)
+		unset = nil
+	}
+	node.FootComment = strings.Join(unset, "\n")
+	return node
+}
+
+// yamlComment returns the specified usage text as YAML comment lines.
+func yamlComment(usage string) []string {
+	if usage == "" {
+		return nil
+	}
+	lines := strings.Split(usage, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight("# "+line, " ")
+	}
+	return lines
+}
+
+// json returns the JSON representation of this node.
+func (n *sampleNode) json() any {
+	scalar := func(s string) any {
+		if n.literal && json.Valid([]byte(s)) && jsonExact(s) {
+			return json.RawMessage(s)
+		}
+		return s
+	}
+	switch value := n.value.(type) {
+	case string:
+		return scalar(value)
+	case []string:
+		elements := make([]any, len(value))
+		for idx, element := range value {
+			elements[idx] = scalar(element)
+		}
+		return elements
+	}
+	mp := map[string]any{}
+	for _, child := range n.children {
+		if !child.unset {
+			mp[child.name] = child.json()
+		}
+	}
+	return mp
+}
+
+// jsonExact returns true if the specified JSON literal survives decoding into
+// a float64, as JSON decoders usually do with numbers: that is, unless it is
+// an integer beyond ±2⁵³.
+func jsonExact(s string) bool {
+	if _, err := strconv.ParseInt(s, 10, 54); err == nil {
+		return true
+	}
+	_, errInt := strconv.ParseInt(s, 10, 64)
+	_, errUint := strconv.ParseUint(s, 10, 64)
+	return errInt != nil && errUint != nil
+}
+
+// toml writes the TOML representation of this node at the specified table
+// path to w: first the settings of this node, then its sub tables.
+func (n *sampleNode) toml(w io.Writer, path []string) error {
+	var b strings.Builder
+	for _, child := range n.children {
+		switch {
+		case child.unset:
+			tomlComment(&b, child.usage)
+			b.WriteString("# " + tomlKey(child.name) + " =\n")
+		case child.value != nil:
+			tomlComment(&b, child.usage)
+			b.WriteString(tomlKey(child.name) + " = " + child.tomlValue() + "\n")
+		}
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	for _, child := range n.children {
+		if !child.isMap() {
+			continue
+		}
+		table := append(slices.Clone(path), child.name)
+		keys := make([]string, len(table))
+		for idx, name := range table {
+			keys[idx] = tomlKey(name)
+		}
+		if _, err := fmt.Fprintf(w, "\n[%s]\n", strings.Join(keys, ".")); err != nil {
+			return err
+		}
+		if err := child.toml(w, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tomlValue returns the TOML representation of this node's value(s).
+func (n *sampleNode) tomlValue() string {
+	scalar := func(s string) string {
+		if n.literal && json.Valid([]byte(s)) && tomlExact(s) {
+			return s
+		}
+		return tomlString(s)
+	}
+	if value, ok := n.value.(string); ok {
+		return scalar(value)
+	}
+	elements := slices.Clone(n.value.([]string))
+	for idx, element := range elements {
+		elements[idx] = scalar(element)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// tomlExact returns true if the specified literal is valid in TOML: that is,
+// unless it is an integer beyond the signed 64 bit range supported by TOML.
+func tomlExact(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return !errors.Is(err, strconv.ErrRange)
+}
+
+// tomlComment writes the specified usage text as TOML comment lines.
+func tomlComment(b *strings.Builder, usage string) {
+	if usage == "" {
+		return
+	}
+	for _, line := range strings.Split(usage, "\n") {
+		b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey returns the specified key as a TOML bare key if possible, and
+// otherwise as a quoted key.
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString returns the specified string as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
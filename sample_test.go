@@ -0,0 +1,235 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+	"github.com/thediveo/deafadder/sub"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("sample configurations", func() {
+
+	var fs *pflag.FlagSet
+	router := sub.Prefixes(
+		sub.Route{Prefix: "http-", Path: []string{"server", "http"}},
+		sub.Route{Prefix: "db-", Path: []string{"db"}},
+	)
+
+	BeforeEach(func() {
+		fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.Int("http-port", 8080, "HTTP listen port")
+		fs.Duration("http-timeout", 90*time.Second, "request timeout")
+		fs.IPNet("http-allow", net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}, "allowed network")
+		fs.StringSlice("db-hosts", []string{"a,b", "c"}, "database hosts\nin order of preference")
+		fs.String("db-name", "0x10", "database name")
+		fs.BoolP("verbose", "v", false, "verbose output")
+		fs.IntSlice("retries", []int{1, 2}, "")
+		fs.String("secret", "", "hidden")
+		Expect(fs.MarkHidden("secret")).To(Succeed())
+		fs.String("old", "", "deprecated")
+		Expect(fs.MarkDeprecated("old", "don't")).To(Succeed())
+	})
+
+	sample := func(format SampleFormat) string {
+		GinkgoHelper()
+		var out strings.Builder
+		Expect(Sample(&out, fs, router, format)).To(Succeed())
+		return out.String()
+	}
+
+	It("generates commented YAML accepted by the getters", func() {
+		out := sample(SampleYAML)
+		Expect(out).To(Equal(`db:
+  # database hosts
+  # in order of preference
+  hosts: ['a,b', c]
+  # database name
+  name: "0x10"
+retries: [1, 2]
+server:
+  http:
+    # allowed network
+    allow: 10.0.0.0/8
+    # HTTP listen port
+    port: 8080
+    # request timeout
+    timeout: 1m30s
+# verbose output
+verbose: false
+`))
+		d := New(koanf.New("."))
+		Expect(d.Load(rawbytes.Provider([]byte(out)), yaml.Parser())).To(Succeed())
+		Expect(d.GetInt("server.http.port")).To(Equal(8080))
+		Expect(d.GetDuration("server.http.timeout")).To(Equal(90 * time.Second))
+		Expect(d.GetIPNet("server.http.allow")).To(Equal(
+			net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}))
+		Expect(d.GetStringSlice("db.hosts")).To(Equal([]string{"a,b", "c"}))
+		Expect(d.GetString("db.name")).To(Equal("0x10"))
+		Expect(d.GetBool("verbose")).To(BeFalse())
+		Expect(d.GetIntSlice("retries")).To(Equal([]int{1, 2}))
+	})
+
+	It("generates TOML", func() {
+		Expect(sample(SampleTOML)).To(Equal(`retries = [1, 2]
+# verbose output
+verbose = false
+
+[db]
+# database hosts
+# in order of preference
+hosts = ["a,b", "c"]
+# database name
+name = "0x10"
+
+[server]
+
+[server.http]
+# allowed network
+allow = "10.0.0.0/8"
+# HTTP listen port
+port = 8080
+# request timeout
+timeout = "1m30s"
+`))
+	})
+
+	It("generates JSON", func() {
+		Expect(sample(SampleJSON)).To(MatchJSON(`{
+  "db": {"hosts": ["a,b", "c"], "name": "0x10"},
+  "retries": [1, 2],
+  "server": {"http": {"allow": "10.0.0.0/8", "port": 8080, "timeout": "1m30s"}},
+  "verbose": false
+}`))
+	})
+
+	It("round-trips the defaults of all flags", func() {
+		_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+		fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.Bool("bool", true, "")
+		fs.Duration("duration", time.Minute, "")
+		fs.DurationSlice("durations", []time.Duration{time.Second}, "")
+		fs.Float32("f32", 0.1234567, "")
+		fs.Float32Slice("f32s", []float32{0.125}, "") // six decimal places only
+		fs.Float64("f64", 0.1234567, "")
+		fs.Float64Slice("f64s", []float64{0.125, 1.5}, "")
+		fs.Int8("i8", -8, "")
+		fs.Int32Slice("i32s", []int32{-32}, "")
+		fs.Uint64("u64", 1<<63, "")
+		fs.UintSlice("uints", []uint{1}, "")
+		fs.IP("ip", net.ParseIP("fe80::1"), "")
+		fs.IP("noip", nil, "no address")
+		fs.IPSlice("ips", []net.IP{net.ParseIP("10.0.0.1")}, "")
+		fs.IPMask("mask", net.CIDRMask(24, 32), "")
+		fs.IPMask("nomask", nil, "")
+		fs.IPNet("net", *ipnet, "")
+		fs.IPNet("nonet", net.IPNet{}, "no network")
+		fs.String("str", "foo", "")
+		fs.StringSlice("strs", []string{"a,b"}, "")
+		defaults := map[string]any{
+			"bool":      true,
+			"duration":  time.Minute,
+			"durations": []time.Duration{time.Second},
+			"f32":       float32(0.1234567),
+			"f32s":      []float32{0.125},
+			"f64":       0.1234567,
+			"f64s":      []float64{0.125, 1.5},
+			"i8":        int8(-8),
+			"i32s":      []int32{-32},
+			"u64":       uint64(1 << 63),
+			"uints":     []uint{1},
+			"ip":        net.ParseIP("fe80::1"),
+			"noip":      nil,
+			"ips":       []net.IP{net.ParseIP("10.0.0.1")},
+			"mask":      net.CIDRMask(24, 32),
+			"nomask":    nil,
+			"net":       *ipnet,
+			"nonet":     nil,
+			"str":       "foo",
+			"strs":      []string{"a,b"},
+		}
+
+		yamlSample := sample(SampleYAML)
+		Expect(yamlSample).To(And(
+			ContainSubstring("# no address\n# noip:\n"),
+			ContainSubstring("# nomask:\n# no network\n# nonet:\n"),
+			Not(ContainSubstring("<nil>"))))
+		Expect(sample(SampleTOML)).To(And(
+			ContainSubstring("# no address\n# noip =\n"),
+			Not(ContainSubstring("<nil>"))))
+
+		for _, sample := range []struct {
+			out    string
+			parser koanf.Parser
+		}{
+			{out: yamlSample, parser: yaml.Parser()},
+			{out: sample(SampleJSON), parser: jsonParser{}},
+		} {
+			d := New(koanf.New("."))
+			Expect(d.Load(rawbytes.Provider([]byte(sample.out)), sample.parser)).To(Succeed())
+			fs.VisitAll(func(flag *pflag.Flag) {
+				expected, ok := defaults[flag.Name]
+				Expect(ok).To(BeTrue(), "flag %s", flag.Name)
+				if expected == nil {
+					Expect(d.Exists(flag.Name)).To(BeFalse(), "flag %s", flag.Name)
+					return
+				}
+				get := Successful(getter(reflect.TypeOf(expected)))
+				Expect(get(d, flag.Name)).To(Equal(expected), "flag %s", flag.Name)
+			})
+		}
+	})
+
+	It("quotes integers beyond the TOML range", func() {
+		fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.Int64("min", math.MinInt64, "")
+		fs.Uint64("max", math.MaxUint64, "")
+		fs.UintSlice("maxs", []uint{math.MaxInt64, math.MaxInt64 + 1}, "")
+		Expect(sample(SampleTOML)).To(Equal(`max = "18446744073709551615"
+maxs = [9223372036854775807, "9223372036854775808"]
+min = -9223372036854775808
+`))
+	})
+
+	It("reports conflicting paths", func() {
+		fs.Int("db", 42, "conflicts with db table")
+		Expect(Sample(&strings.Builder{}, fs, router, SampleYAML)).To(MatchError(ContainSubstring(
+			"flag db-hosts conflicts with flag for configuration setting db")))
+		fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		fs.Int("db-name", 42, "")
+		fs.Int("name", 42, "")
+		mapping := sub.Mapping(map[string][]string{
+			"db-name": {"db", "name"},
+			"name":    {"db", "name"},
+		})
+		Expect(Sample(&strings.Builder{}, fs, mapping, SampleYAML)).To(MatchError(
+			"flag name conflicts with configuration setting db.name"))
+		Expect(Sample(&strings.Builder{}, fs, nil, SampleFormat(42))).To(MatchError(
+			"unknown sample format 42"))
+	})
+
+})
@@ -0,0 +1,217 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a recurring schedule, either in the classic five-field cron
+// format “minute hour day-of-month month day-of-week”, such as “30 2 * * 1-5”,
+// or as one of the descriptors “@yearly”, “@monthly”, “@weekly”, “@daily”,
+// “@hourly”, and “@every <duration>”, such as “@every 90m”.
+//
+// Cron fields support “*”, single values, ranges “a-b”, steps “*/n” and
+// “a-b/n”, and comma-separated lists thereof; months and days of the week can
+// also be given by their three-letter English names, such as “jan” and “mon”.
+// Both 0 and 7 mean Sunday. If both day-of-month and day-of-week are
+// restricted, a day matches if either matches, as in classic cron.
+type Schedule struct {
+	spec   string
+	every  time.Duration
+	fields [5]uint64 // bit sets of minutes, hours, days, months, weekdays
+	dom    bool      // day-of-month restricted
+	dow    bool      // day-of-week restricted
+}
+
+// scheduleField describes the allowed values of a cron field.
+type scheduleField struct {
+	min, max int
+	names    []string // names of the values, starting at min
+}
+
+var scheduleFields = [5]scheduleField{
+	{min: 0, max: 59},
+	{min: 0, max: 23},
+	{min: 1, max: 31},
+	{min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses the specified textual schedule, see [Schedule].
+func ParseSchedule(s string) (*Schedule, error) {
+	spec := strings.Join(strings.Fields(s), " ")
+	if prefix := "@every "; len(spec) > len(prefix) && strings.EqualFold(spec[:len(prefix)], prefix) {
+		every := spec[len(prefix):]
+		interval, err := time.ParseDuration(every)
+		if err != nil {
+			return nil, err
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("non-positive schedule interval %s", every)
+		}
+		return &Schedule{spec: spec, every: interval}, nil
+	}
+	cron := spec
+	if descriptor, ok := scheduleDescriptors[strings.ToLower(spec)]; ok {
+		cron = descriptor
+	}
+	fields := strings.Fields(cron)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("invalid schedule %q, expected 5 fields", s)
+	}
+	sched := &Schedule{spec: spec}
+	for idx, field := range fields {
+		bits, err := scheduleFields[idx].parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", s, err)
+		}
+		sched.fields[idx] = bits
+	}
+	if sched.fields[4]&(1<<7) != 0 {
+		sched.fields[4] |= 1 // Sunday
+	}
+	sched.dom = fields[2] != "*"
+	sched.dow = fields[4] != "*"
+	return sched, nil
+}
+
+// parse the specified textual field, returning the bit set of its values.
+func (f scheduleField) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepText)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			loText, hiText, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(loText); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiText); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value returns the numeric value of the specified textual value or name.
+func (f scheduleField) value(s string) (int, error) {
+	for idx, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + idx, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the textual schedule.
+func (s *Schedule) String() string { return s.spec }
+
+// Next returns the next time after t that matches this schedule, in the
+// location of t, or the zero time if there is no such time within the next
+// five years, such as for “0 0 30 2 *”.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.has(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.has(1, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.has(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) has(field int, v int) bool { return s.fields[field]&(1<<v) != 0 }
+
+// day returns true if the day of t matches the day-of-month and day-of-week
+// fields.
+func (s *Schedule) day(t time.Time) bool {
+	dom := s.has(2, t.Day())
+	dow := s.has(4, int(t.Weekday()))
+	if s.dom && s.dow {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// GetSchedule returns the *Schedule value of a configuration setting with the
+// given name, such as “30 2 * * mon-fri” or “@every 15m”.
+func (d *DeafAdder) GetSchedule(path string) (v *Schedule, err error) {
+	return parse(d, path, func(s string) (*Schedule, error) {
+		if strings.TrimSpace(s) == "" {
+			return nil, errors.New("empty schedule")
+		}
+		return ParseSchedule(s)
+	})
+}
+
+// SetSchedule sets the configuration setting with the given name to the
+// specified *Schedule value.
+func (d *DeafAdder) SetSchedule(path string, v *Schedule) error {
+	return d.set(path, v.String())
+}
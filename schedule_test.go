@@ -0,0 +1,80 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("schedules", func() {
+
+	// Wednesday, 2025-01-01 12:34:56 UTC
+	now := time.Date(2025, 1, 1, 12, 34, 56, 0, time.UTC)
+
+	DescribeTable("next times",
+		func(spec string, expected time.Time) {
+			Expect(Successful(ParseSchedule(spec)).Next(now)).To(Equal(expected))
+		},
+		Entry(nil, "* * * * *", time.Date(2025, 1, 1, 12, 35, 0, 0, time.UTC)),
+		Entry(nil, "*/15 * * * *", time.Date(2025, 1, 1, 12, 45, 0, 0, time.UTC)),
+		Entry(nil, "30 2 * * mon-fri", time.Date(2025, 1, 2, 2, 30, 0, 0, time.UTC)),
+		Entry(nil, "0 0 * * 7", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+		Entry(nil, "0 9 15 * sat", time.Date(2025, 1, 4, 9, 0, 0, 0, time.UTC)),
+		Entry(nil, "0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry(nil, "0 0 30 2 *", time.Time{}),
+		Entry(nil, "@hourly", time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)),
+		Entry(nil, "@monthly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		Entry(nil, "@every 90m", now.Add(90*time.Minute)),
+		Entry(nil, "@EVERY 1h", now.Add(time.Hour)),
+		Entry(nil, "@Daily", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+	)
+
+	DescribeTable("rejects invalid schedules",
+		func(spec string, msg string) {
+			Expect(ParseSchedule(spec)).Error().To(MatchError(ContainSubstring(msg)))
+		},
+		Entry(nil, "* * * *", "expected 5 fields"),
+		Entry(nil, "60 * * * *", `invalid value "60", expected 0-59`),
+		Entry(nil, "5-1 * * * *", `invalid range "5-1"`),
+		Entry(nil, "*/0 * * * *", `invalid step "0"`),
+		Entry(nil, "* * * foo *", `invalid value "foo"`),
+		Entry(nil, "@every -5m", "non-positive schedule interval"),
+		Entry(nil, "@every soon", "invalid duration"),
+	)
+
+	It("retrieves and sets schedules", func() {
+		d := load(`
+backup: "30  2 * * mon-fri"
+sync: "@every 15m"
+bad: "@sometimes"
+empty: ""
+`)
+		sched := Successful(d.GetSchedule("backup"))
+		Expect(sched.String()).To(Equal("30 2 * * mon-fri"))
+		Expect(Successful(Get[*Schedule](d, "sync")).Next(now)).To(Equal(now.Add(15 * time.Minute)))
+		Expect(d.GetSchedule("bad")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad")))
+		Expect(d.GetSchedule("empty")).Error().To(MatchError(ContainSubstring("empty schedule")))
+
+		Expect(d.SetSchedule("copy", sched)).To(Succeed())
+		Expect(Successful(d.GetSchedule("copy")).Next(now)).To(Equal(sched.Next(now)))
+		Expect(Format(sched)).To(Equal("30 2 * * mon-fri"))
+	})
+
+})
@@ -0,0 +1,183 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Redacted is what secrets render as when printed, logged, or marshalled.
+const Redacted = "[REDACTED]"
+
+// Secret is a sensitive configuration value, such as a password. Secrets
+// redact themselves when printed, logged, or marshalled; use [Secret.Reveal]
+// in order to get the actual secret value.
+type Secret struct {
+	value string
+}
+
+// NewSecret returns a new Secret with the specified value.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the actual secret value.
+func (s Secret) Reveal() string { return s.value }
+
+// String returns the redacted secret.
+func (s Secret) String() string { return Redacted }
+
+// GoString returns the redacted secret.
+func (s Secret) GoString() string { return Redacted }
+
+// Format writes the redacted secret, regardless of the verb.
+func (s Secret) Format(f fmt.State, _ rune) { _, _ = io.WriteString(f, Redacted) }
+
+// LogValue returns the redacted secret as a slog.Value.
+func (s Secret) LogValue() slog.Value { return slog.StringValue(Redacted) }
+
+// MarshalText returns the redacted secret.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(Redacted), nil }
+
+// SecretResolver resolves a secret reference, such as the name of a file
+// containing the secret, into the secret value.
+type SecretResolver func(ref string) (string, error)
+
+// FileSecret is a [SecretResolver] reading the secret from the file named by
+// the reference, stripping any trailing newlines.
+func FileSecret(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// EnvSecret is a [SecretResolver] returning the value of the environment
+// variable named by the reference.
+func EnvSecret(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s not set", ref)
+	}
+	return value, nil
+}
+
+// CommandSecret is a [SecretResolver] running the command given by the
+// reference and returning the command's output, stripping any trailing
+// newlines. The reference is split into the command and its arguments at
+// white space; it is not passed to a shell.
+//
+// As running commands from configuration files is a security-relevant
+// decision, CommandSecret isn't enabled by default but needs to be enabled
+// explicitly using [WithSecretResolver], such as:
+//
+//	d := deafadder.New(k, deafadder.WithSecretResolver("cmd", deafadder.CommandSecret))
+func CommandSecret(ref string) (string, error) {
+	args := strings.Fields(ref)
+	if len(args) == 0 {
+		return "", errors.New("empty secret command")
+	}
+	out, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("secret command %s failed: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// WithSecretResolver registers the specified resolver for secret references
+// with the given scheme, such as “vault” for “vault:db/password”. The “file”
+// and “env” schemes are registered by default, using [FileSecret] and
+// [EnvSecret]; registering a resolver for them replaces the default.
+func WithSecretResolver(scheme string, resolver SecretResolver) Option {
+	return func(d *DeafAdder) {
+		if d.opts.resolvers == nil {
+			d.opts.resolvers = map[string]SecretResolver{}
+		}
+		d.opts.resolvers[scheme] = resolver
+	}
+}
+
+// WithSecrets marks the configuration settings with the given names as
+// secrets, so that they get redacted from configuration dumps even when not
+// (yet) retrieved using [DeafAdder.GetSecret].
+func WithSecrets(paths ...string) Option {
+	return func(d *DeafAdder) {
+		for _, path := range paths {
+			d.opts.markSecret(path)
+		}
+	}
+}
+
+// GetSecret returns the Secret value of a configuration setting with the
+// given name. If the value is a reference of the form “scheme:ref” with a
+// registered scheme, such as “file:/run/secrets/db” or “env:DB_PASSWORD”, the
+// secret value gets resolved accordingly; all other values are taken
+// literally. Additionally, GetSecret marks the configuration setting as a
+// secret, see also [DeafAdder.IsSecret].
+func (d *DeafAdder) GetSecret(path string) (v Secret, err error) {
+	d.settings().markSecret(d.abs(path))
+	return parse(d, path, d.resolveSecret)
+}
+
+// IsSecret returns true if the configuration setting with the given name is a
+// secret, either because it was marked using [WithSecrets] or because it was
+// retrieved using [DeafAdder.GetSecret].
+func (d *DeafAdder) IsSecret(path string) bool {
+	o := d.settings()
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.secrets[d.abs(path)]
+}
+
+// resolveSecret resolves the specified secret value or reference.
+func (d *DeafAdder) resolveSecret(s string) (Secret, error) {
+	scheme, ref, ok := strings.Cut(s, ":")
+	if !ok {
+		return Secret{value: s}, nil
+	}
+	resolver, ok := d.settings().resolvers[scheme]
+	if !ok {
+		switch scheme {
+		case "file":
+			resolver = FileSecret
+		case "env":
+			resolver = EnvSecret
+		default:
+			return Secret{value: s}, nil
+		}
+	}
+	value, err := resolver(ref)
+	if err != nil {
+		return Secret{}, fmt.Errorf("cannot resolve %s secret: %w", scheme, err)
+	}
+	return Secret{value: value}, nil
+}
+
+// markSecret marks the configuration setting with the given name as secret.
+func (o *options) markSecret(path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.secrets == nil {
+		o.secrets = map[string]bool{}
+	}
+	o.secrets[path] = true
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("secrets", func() {
+
+	It("redacts itself", func() {
+		s := NewSecret("sesame")
+		Expect(s.Reveal()).To(Equal("sesame"))
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+			Expect(fmt.Sprintf(format, s)).To(Equal(Redacted), format)
+		}
+		Expect(fmt.Sprint(s)).To(Equal(Redacted))
+		Expect(string(Successful(json.Marshal(struct{ Password Secret }{s})))).To(
+			Equal(`{"Password":"[REDACTED]"}`))
+
+		var buf bytes.Buffer
+		slog.New(slog.NewTextHandler(&buf, nil)).Info("login", "password", s)
+		Expect(buf.String()).To(ContainSubstring("password=[REDACTED]"))
+		Expect(buf.String()).NotTo(ContainSubstring("sesame"))
+	})
+
+	It("resolves secret references", func() {
+		tmp := GinkgoT().TempDir()
+		secretFile := writeFile(tmp, "db", "file-sesame\n")
+		GinkgoT().Setenv("DEAFADDER_TEST_SECRET", "env-sesame")
+
+		d := New(koanf.New("."),
+			WithSecretResolver("cmd", CommandSecret),
+			WithSecretResolver("vault", func(ref string) (string, error) {
+				if ref == "db" {
+					return "vault-sesame", nil
+				}
+				return "", errors.New("sealed")
+			}))
+		Expect(d.Load(rawbytes.Provider([]byte(fmt.Sprintf(`
+literal: sesame
+unknown-scheme: "foo:bar"
+file: "file:%s"
+env: "env:DEAFADDER_TEST_SECRET"
+cmd: "cmd:echo cmd-sesame"
+vault: "vault:db"
+bad-env: "env:DEAFADDER_TEST_NADA"
+bad-vault: "vault:nada"
+`, secretFile))), yaml.Parser())).To(Succeed())
+
+		for path, expected := range map[string]string{
+			"literal":        "sesame",
+			"unknown-scheme": "foo:bar",
+			"file":           "file-sesame",
+			"env":            "env-sesame",
+			"cmd":            "cmd-sesame",
+			"vault":          "vault-sesame",
+		} {
+			Expect(d.IsSecret(path)).To(BeFalse())
+			Expect(Successful(d.GetSecret(path)).Reveal()).To(Equal(expected), path)
+			Expect(d.IsSecret(path)).To(BeTrue())
+		}
+		Expect(d.GetSecret("bad-env")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad-env: cannot resolve env secret")))
+		_, err := d.GetSecret("bad-vault")
+		Expect(err).To(MatchError(ContainSubstring("sealed")))
+		var cerr *ConversionError
+		Expect(errors.As(err, &cerr)).To(BeTrue())
+		Expect(cerr.Value).To(Equal(Redacted))
+	})
+
+	It("doesn't run commands by default", func() {
+		d := load(`cmd: "cmd:echo foo"`)
+		Expect(Successful(d.GetSecret("cmd")).Reveal()).To(Equal("cmd:echo foo"))
+	})
+
+	It("marks secrets", func() {
+		d := New(koanf.New("."), WithSecrets("db.password"))
+		Expect(d.IsSecret("db.password")).To(BeTrue())
+		Expect(d.IsSecret("db.user")).To(BeFalse())
+	})
+
+	It("redacts secrets in conversion errors", func() {
+		d := New(koanf.New("."), WithSecrets("db.pin", "db.pins"))
+		Expect(d.Load(mapProvider{"db": map[string]any{
+			"pin":  "sesame",
+			"pins": []any{"1234", "sesame"},
+			"port": "sesame",
+		}}, nil)).To(Succeed())
+		var cerr *ConversionError
+		_, err := d.GetInt("db.pin")
+		Expect(errors.As(err, &cerr)).To(BeTrue())
+		Expect(cerr.Value).To(Equal(Redacted))
+		_, err = d.GetIntSlice("db.pins")
+		Expect(errors.As(err, &cerr)).To(BeTrue())
+		Expect(cerr.Value).To(Equal(Redacted))
+		_, err = d.GetAddrSlice("db.pins")
+		Expect(errors.As(err, &cerr)).To(BeTrue())
+		Expect(cerr.Path).To(Equal("db.pins[0]"))
+		Expect(cerr.Value).To(Equal(Redacted))
+
+		_, err = d.GetInt("db.port")
+		Expect(errors.As(err, &cerr)).To(BeTrue())
+		Expect(cerr.Value).To(Equal("sesame"))
+	})
+
+})
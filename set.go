@@ -0,0 +1,437 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// SetBool sets the configuration setting with the given name to the specified
+// bool value.
+func (d *DeafAdder) SetBool(path string, v bool) error {
+	return set(d, path, v, (*pflag.FlagSet).Bool, false)
+}
+
+// SetBytesBase64 sets the configuration setting with the given name to the
+// specified []byte value in base64 encoding.
+func (d *DeafAdder) SetBytesBase64(path string, v []byte) error {
+	return set(d, path, v, (*pflag.FlagSet).BytesBase64, true)
+}
+
+// SetBytesHex sets the configuration setting with the given name to the
+// specified []byte value in hex encoding.
+func (d *DeafAdder) SetBytesHex(path string, v []byte) error {
+	return set(d, path, v, (*pflag.FlagSet).BytesHex, true)
+}
+
+// SetByteSize sets the configuration setting with the given name to the
+// specified ByteSize value.
+func (d *DeafAdder) SetByteSize(path string, v ByteSize) error {
+	return set(d, path, v, byteSizeConstructor, false)
+}
+
+// SetByteSizeSlice sets the configuration setting with the given name to the
+// specified []ByteSize value.
+func (d *DeafAdder) SetByteSizeSlice(path string, v []ByteSize) error {
+	return set(d, path, v, byteSizeSliceConstructor, false)
+}
+
+// SetCount sets the configuration setting with the given name to the specified
+// count value.
+func (d *DeafAdder) SetCount(path string, v int) error {
+	return d.set(path, strconv.Itoa(v))
+}
+
+// SetDuration sets the configuration setting with the given name to the
+// specified time.Duration value.
+func (d *DeafAdder) SetDuration(path string, v time.Duration) error {
+	return set(d, path, v, (*pflag.FlagSet).Duration, false)
+}
+
+// SetDurationSlice sets the configuration setting with the given name to the
+// specified []time.Duration value.
+func (d *DeafAdder) SetDurationSlice(path string, v []time.Duration) error {
+	return set(d, path, v, (*pflag.FlagSet).DurationSlice, false)
+}
+
+// SetFloat32 sets the configuration setting with the given name to the
+// specified float32 value.
+func (d *DeafAdder) SetFloat32(path string, v float32) error {
+	return set(d, path, v, (*pflag.FlagSet).Float32, false)
+}
+
+// SetFloat32Slice sets the configuration setting with the given name to the
+// specified []float32 value.
+func (d *DeafAdder) SetFloat32Slice(path string, v []float32) error {
+	return setStrings(d, path, v, formatFloat32)
+}
+
+// SetFloat64 sets the configuration setting with the given name to the
+// specified float64 value.
+func (d *DeafAdder) SetFloat64(path string, v float64) error {
+	return set(d, path, v, (*pflag.FlagSet).Float64, false)
+}
+
+// SetFloat64Slice sets the configuration setting with the given name to the
+// specified []float64 value.
+func (d *DeafAdder) SetFloat64Slice(path string, v []float64) error {
+	return setStrings(d, path, v, formatFloat64)
+}
+
+// SetInt sets the configuration setting with the given name to the specified
+// int value.
+func (d *DeafAdder) SetInt(path string, v int) error {
+	return set(d, path, v, (*pflag.FlagSet).Int, false)
+}
+
+// SetIntSlice sets the configuration setting with the given name to the
+// specified []int value.
+func (d *DeafAdder) SetIntSlice(path string, v []int) error {
+	return set(d, path, v, (*pflag.FlagSet).IntSlice, false)
+}
+
+// SetInt8 sets the configuration setting with the given name to the specified
+// int8 value.
+func (d *DeafAdder) SetInt8(path string, v int8) error {
+	return set(d, path, v, (*pflag.FlagSet).Int8, false)
+}
+
+// SetInt16 sets the configuration setting with the given name to the specified
+// int16 value.
+func (d *DeafAdder) SetInt16(path string, v int16) error {
+	return set(d, path, v, (*pflag.FlagSet).Int16, false)
+}
+
+// SetInt32 sets the configuration setting with the given name to the specified
+// int32 value.
+func (d *DeafAdder) SetInt32(path string, v int32) error {
+	return set(d, path, v, (*pflag.FlagSet).Int32, false)
+}
+
+// SetInt32Slice sets the configuration setting with the given name to the
+// specified []int32 value.
+func (d *DeafAdder) SetInt32Slice(path string, v []int32) error {
+	return set(d, path, v, (*pflag.FlagSet).Int32Slice, false)
+}
+
+// SetInt64 sets the configuration setting with the given name to the specified
+// int64 value.
+func (d *DeafAdder) SetInt64(path string, v int64) error {
+	return set(d, path, v, (*pflag.FlagSet).Int64, false)
+}
+
+// SetInt64Slice sets the configuration setting with the given name to the
+// specified []int64 value.
+func (d *DeafAdder) SetInt64Slice(path string, v []int64) error {
+	return set(d, path, v, (*pflag.FlagSet).Int64Slice, false)
+}
+
+// SetIP sets the configuration setting with the given name to the specified
+// net.IP value.
+func (d *DeafAdder) SetIP(path string, v net.IP) error {
+	if err := checkIP(v); err != nil {
+		return err
+	}
+	return set(d, path, v, (*pflag.FlagSet).IP, true)
+}
+
+// SetIPSlice sets the configuration setting with the given name to the
+// specified []net.IP value.
+func (d *DeafAdder) SetIPSlice(path string, v []net.IP) error {
+	for _, ip := range v {
+		if err := checkIP(ip); err != nil {
+			return err
+		}
+	}
+	return set(d, path, v, (*pflag.FlagSet).IPSlice, false)
+}
+
+// SetIPMask sets the configuration setting with the given name to the
+// specified net.IPMask value.
+func (d *DeafAdder) SetIPMask(path string, v net.IPMask) error {
+	if err := checkIPMask(v); err != nil {
+		return err
+	}
+	return set(d, path, v, (*pflag.FlagSet).IPMask, true)
+}
+
+// SetIPNet sets the configuration setting with the given name to the
+// specified net.IPNet value.
+func (d *DeafAdder) SetIPNet(path string, v net.IPNet) error {
+	if err := checkIPNet(v); err != nil {
+		return err
+	}
+	return set(d, path, v, (*pflag.FlagSet).IPNet, false)
+}
+
+// SetIPNetSlice sets the configuration setting with the given name to the
+// specified []net.IPNet value.
+func (d *DeafAdder) SetIPNetSlice(path string, v []net.IPNet) error {
+	for _, ipnet := range v {
+		if err := checkIPNet(ipnet); err != nil {
+			return err
+		}
+	}
+	return set(d, path, v, (*pflag.FlagSet).IPNetSlice, false)
+}
+
+// SetString sets the configuration setting with the given name to the
+// specified string value.
+func (d *DeafAdder) SetString(path string, v string) error {
+	return set(d, path, v, (*pflag.FlagSet).String, false)
+}
+
+// SetStringArray sets the configuration setting with the given name to the
+// specified []string value, keeping any commas inside the individual strings.
+func (d *DeafAdder) SetStringArray(path string, v []string) error {
+	return set(d, path, v, (*pflag.FlagSet).StringArray, false)
+}
+
+// SetStringSlice sets the configuration setting with the given name to the
+// specified []string value.
+func (d *DeafAdder) SetStringSlice(path string, v []string) error {
+	return set(d, path, v, (*pflag.FlagSet).StringSlice, false)
+}
+
+// SetUint sets the configuration setting with the given name to the specified
+// uint value.
+func (d *DeafAdder) SetUint(path string, v uint) error {
+	return set(d, path, v, (*pflag.FlagSet).Uint, false)
+}
+
+// SetUintSlice sets the configuration setting with the given name to the
+// specified []uint value.
+func (d *DeafAdder) SetUintSlice(path string, v []uint) error {
+	return set(d, path, v, (*pflag.FlagSet).UintSlice, false)
+}
+
+// SetUint8 sets the configuration setting with the given name to the specified
+// uint8 value.
+func (d *DeafAdder) SetUint8(path string, v uint8) error {
+	return set(d, path, v, (*pflag.FlagSet).Uint8, false)
+}
+
+// SetUint16 sets the configuration setting with the given name to the
+// specified uint16 value.
+func (d *DeafAdder) SetUint16(path string, v uint16) error {
+	return set(d, path, v, (*pflag.FlagSet).Uint16, false)
+}
+
+// SetUint32 sets the configuration setting with the given name to the
+// specified uint32 value.
+func (d *DeafAdder) SetUint32(path string, v uint32) error {
+	return set(d, path, v, (*pflag.FlagSet).Uint32, false)
+}
+
+// SetUint64 sets the configuration setting with the given name to the
+// specified uint64 value.
+func (d *DeafAdder) SetUint64(path string, v uint64) error {
+	return set(d, path, v, (*pflag.FlagSet).Uint64, false)
+}
+
+// SetText sets the configuration setting with the given name to the textual
+// form of the specified value, using its [encoding.TextMarshaler]
+// implementation.
+func (d *DeafAdder) SetText(path string, v encoding.TextMarshaler) error {
+	text, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	return d.set(path, string(text))
+}
+
+// checkIP, checkIPMask, and checkIPNet return an error for nil or otherwise
+// invalid IP addresses, masks, and networks, as their textual forms, such as
+// “<nil>”, wouldn't parse back.
+func checkIP(ip net.IP) error {
+	if ip.To16() == nil {
+		return fmt.Errorf("invalid IP address %q", ip.String())
+	}
+	return nil
+}
+
+func checkIPMask(mask net.IPMask) error {
+	if len(mask) != net.IPv4len && len(mask) != net.IPv6len {
+		return fmt.Errorf("invalid IP mask %q", mask.String())
+	}
+	return nil
+}
+
+func checkIPNet(ipnet net.IPNet) error {
+	if ipnet.IP.To16() == nil || checkIPMask(ipnet.Mask) != nil {
+		return fmt.Errorf("invalid IP network %q", ipnet.String())
+	}
+	return nil
+}
+
+// set sets the configuration setting with the given name to the textual form
+// of the specified value of type T, using the pflag conversion rules of the
+// specified flag constructor. Slice values are stored as []string.
+func set[T any](d *DeafAdder, path string, v T, fn func(*pflag.FlagSet, string, T, string) *T, treatAsScalar bool) error {
+	value, err := text(v, fn, treatAsScalar)
+	if err != nil {
+		return err
+	}
+	return d.set(path, value)
+}
+
+// text returns the textual form of the specified value of type T, using the
+// pflag conversion rules of the specified flag constructor: a string for scalar
+// values and a []string for slice values.
+func text[T any](v T, fn func(*pflag.FlagSet, string, T, string) *T, treatAsScalar bool) (any, error) {
+	fs := pflag.NewFlagSet("deafadder-dummy-flagset", pflag.ContinueOnError)
+	fn(fs, flagName, v, "")
+	value := fs.Lookup(flagName).Value
+	if treatAsScalar || reflect.TypeFor[T]().Kind() != reflect.Slice {
+		return value.String(), nil
+	}
+	if sv, ok := value.(pflag.SliceValue); ok {
+		return sv.GetSlice(), nil
+	}
+	// Not all pflag slice values implement pflag.SliceValue, such as the
+	// []net.IPNet slice value, so we need to fall back to parsing the textual
+	// "[a,b,c]" representation as used by pflag.
+	s := strings.TrimSuffix(strings.TrimPrefix(value.String(), "["), "]")
+	if s == "" {
+		return []string{}, nil
+	}
+	return csv.NewReader(strings.NewReader(s)).Read()
+}
+
+// formatFloat32 and formatFloat64 return the shortest textual forms of float
+// values that parse back into exactly the same values. The pflag float slice
+// values unfortunately render their elements with only six decimal places, so
+// we cannot use them here.
+func formatFloat32(v float32) string { return strconv.FormatFloat(float64(v), 'g', -1, 32) }
+func formatFloat64(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+// setStrings sets the configuration setting with the given name to the
+// textual forms of the specified values, using the specified format function.
+func setStrings[T any](d *DeafAdder, path string, vs []T, format func(T) string) error {
+	sl := make([]string, len(vs))
+	for idx, v := range vs {
+		sl[idx] = format(v)
+	}
+	return d.set(path, sl)
+}
+
+// set sets the configuration setting with the given name to the specified
+// value, removing any settings shadowed by it, such as deprecated aliases and
+// differently spelled keys that match after normalization. For views, set
+// sets the value in the top-level DeafAdder object as well as in the view's
+// own snapshot.
+func (d *DeafAdder) set(path string, value any) error {
+	shadows := d.top().shadows(d.abs(path))
+	if d.root != nil {
+		if err := d.root.set(d.abs(path), value); err != nil {
+			return err
+		}
+		var relative []string
+		for _, key := range shadows {
+			if key, ok := strings.CutPrefix(key, d.prefix+d.Delim()); ok || d.prefix == "" {
+				relative = append(relative, key)
+			}
+		}
+		shadows = relative
+	}
+	if err := d.Set(path, value); err != nil {
+		return err
+	}
+	for _, key := range shadows {
+		d.Delete(key)
+	}
+	return nil
+}
+
+// shadows returns the names of the configuration settings that would otherwise
+// conflict or collide with the configuration setting at the specified absolute
+// path, that is, its deprecated aliases as well as keys matching it or its
+// aliases after normalization.
+func (d *DeafAdder) shadows(path string) []string {
+	o := d.settings()
+	delim := d.Delim()
+	paths := []string{path}
+	for _, alias := range o.aliases {
+		switch {
+		case path == alias.new:
+			paths = append(paths, alias.old)
+		case strings.HasPrefix(path, alias.new+delim):
+			paths = append(paths, alias.old+path[len(alias.new):])
+		}
+	}
+	var shadows []string
+	for _, p := range paths {
+		if p != path && d.Exists(p) {
+			shadows = append(shadows, p)
+		}
+		if o.normalize == nil {
+			continue
+		}
+		for _, key := range d.normalizedKeys()[d.normalizePath(p)] {
+			if key != path && key != p {
+				shadows = append(shadows, key)
+			}
+		}
+	}
+	return shadows
+}
+
+// SaveFile marshals the configuration using the specified parser, such as the
+// koanf YAML or JSON parsers, and writes it into the named file. SaveFile
+// atomically replaces any existing file, keeping its permissions, so that
+// readers never see a partially written configuration file.
+func (d *DeafAdder) SaveFile(name string, parser koanf.Parser) error {
+	b, err := d.current().Marshal(parser)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }() // no-op after successful rename
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
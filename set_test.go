@@ -0,0 +1,209 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+// jsonParser implements a koanf.Parser for JSON.
+type jsonParser struct{}
+
+func (jsonParser) Unmarshal(b []byte) (map[string]any, error) {
+	var mp map[string]any
+	return mp, json.Unmarshal(b, &mp)
+}
+
+func (jsonParser) Marshal(mp map[string]any) ([]byte, error) { return json.Marshal(mp) }
+
+var _ = Describe("setting configuration values", func() {
+
+	It("round-trips pflag values", func() {
+		d := New(koanf.New("."))
+		_, ipnet, _ := net.ParseCIDR("10.0.0.0/8")
+		_, ipnet6, _ := net.ParseCIDR("fe80::/64")
+
+		Expect(d.SetBool("bool", true)).To(Succeed())
+		Expect(d.SetBytesBase64("b64", []byte("foo"))).To(Succeed())
+		Expect(d.SetBytesHex("hex", []byte{0xde, 0xad})).To(Succeed())
+		Expect(d.SetByteSize("size", 3*GiB)).To(Succeed())
+		Expect(d.SetByteSizeSlice("sizes", []ByteSize{KB, 2 * MiB})).To(Succeed())
+		Expect(d.SetCount("verbosity", 3)).To(Succeed())
+		Expect(d.SetDuration("timeout", 90*time.Second)).To(Succeed())
+		Expect(d.SetDurationSlice("backoffs", []time.Duration{time.Second, time.Minute})).To(Succeed())
+		Expect(d.SetFloat64("pi", 3.14159)).To(Succeed())
+		Expect(d.SetFloat32Slice("f32s", []float32{0.1234567})).To(Succeed())
+		Expect(d.SetFloat64Slice("f64s", []float64{0.1234567, 3.14159265358979})).To(Succeed())
+		Expect(d.SetInt8("i8", -128)).To(Succeed())
+		Expect(d.SetIntSlice("ints", []int{1, -2})).To(Succeed())
+		Expect(d.SetUint64("u64", 1<<63)).To(Succeed())
+		Expect(d.SetIP("ip", net.ParseIP("fe80::1"))).To(Succeed())
+		Expect(d.SetIPSlice("ips", []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")})).To(Succeed())
+		Expect(d.SetIPMask("mask", net.CIDRMask(24, 32))).To(Succeed())
+		Expect(d.SetIPNet("net", *ipnet)).To(Succeed())
+		Expect(d.SetIPNetSlice("nets", []net.IPNet{*ipnet, *ipnet6})).To(Succeed())
+		Expect(d.SetStringArray("arr", []string{"a,b", `"c"`})).To(Succeed())
+		Expect(d.SetStringSlice("strs", []string{"a,b", "c"})).To(Succeed())
+
+		Expect(d.Get("timeout")).To(Equal("1m30s"))
+		Expect(d.Get("nets")).To(Equal([]string{"10.0.0.0/8", "fe80::/64"}))
+
+		Expect(d.GetBool("bool")).To(BeTrue())
+		Expect(d.GetBytesBase64("b64")).To(Equal([]byte("foo")))
+		Expect(d.GetBytesHex("hex")).To(Equal([]byte{0xde, 0xad}))
+		Expect(d.GetByteSize("size")).To(Equal(3 * GiB))
+		Expect(d.GetByteSizeSlice("sizes")).To(Equal([]ByteSize{KB, 2 * MiB}))
+		Expect(d.GetCount("verbosity")).To(Equal(3))
+		Expect(d.GetDuration("timeout")).To(Equal(90 * time.Second))
+		Expect(d.GetDurationSlice("backoffs")).To(Equal([]time.Duration{time.Second, time.Minute}))
+		Expect(d.GetFloat64("pi")).To(Equal(3.14159))
+		Expect(d.GetFloat32Slice("f32s")).To(Equal([]float32{0.1234567}))
+		Expect(d.GetFloat64Slice("f64s")).To(Equal([]float64{0.1234567, 3.14159265358979}))
+		Expect(d.GetInt8("i8")).To(Equal(int8(-128)))
+		Expect(d.GetIntSlice("ints")).To(Equal([]int{1, -2}))
+		Expect(d.GetUint64("u64")).To(Equal(uint64(1 << 63)))
+		Expect(d.GetIP("ip")).To(Equal(net.ParseIP("fe80::1")))
+		Expect(d.GetIPSlice("ips")).To(HaveLen(2))
+		Expect(d.GetIPMask("mask")).To(Equal(net.CIDRMask(24, 32)))
+		Expect(d.GetIPNet("net")).To(Equal(*ipnet))
+		Expect(d.GetIPNetSlice("nets")).To(HaveLen(2))
+		Expect(d.GetStringArray("arr")).To(Equal([]string{"a,b", `"c"`}))
+		Expect(d.GetStringSlice("strs")).To(Equal([]string{"a,b", "c"}))
+	})
+
+	It("rejects nil IP addresses, masks, and networks", func() {
+		d := New(koanf.New("."))
+		Expect(d.SetIP("ip", nil)).To(MatchError(`invalid IP address "<nil>"`))
+		Expect(d.SetIPSlice("ips", []net.IP{net.ParseIP("::1"), nil})).To(MatchError(
+			`invalid IP address "<nil>"`))
+		Expect(d.SetIPMask("mask", nil)).To(MatchError(`invalid IP mask "<nil>"`))
+		Expect(d.SetIPNet("net", net.IPNet{})).To(MatchError(`invalid IP network "<nil>"`))
+		Expect(d.SetIPNetSlice("nets", []net.IPNet{{}})).To(MatchError(`invalid IP network "<nil>"`))
+		Expect(d.Keys()).To(BeEmpty())
+	})
+
+	It("round-trips other values", func() {
+		d := New(koanf.New("."))
+		now := time.Date(2025, 6, 1, 12, 34, 56, 789, time.UTC)
+		u := Successful(url.Parse("https://example.org/foo?bar=baz"))
+		formats := Enum{Allowed: []string{"json", "text"}, Aliases: map[string]string{"plain": "text"}}
+
+		Expect(d.SetAddr("addr", netip.MustParseAddr("fe80::1%eth0"))).To(Succeed())
+		Expect(d.SetPrefixSlice("prefixes", []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})).To(Succeed())
+		Expect(d.SetAddrPort("addrport", netip.MustParseAddrPort("[::1]:80"))).To(Succeed())
+		Expect(d.SetTime("time", now)).To(Succeed())
+		Expect(d.SetLocation("tz", time.UTC)).To(Succeed())
+		Expect(d.SetURLSlice("urls", []*url.URL{u})).To(Succeed())
+		Expect(d.SetRegexp("re", regexp.MustCompile(`^a+$`))).To(Succeed())
+		Expect(d.SetEnum("format", "plain", formats)).To(Succeed())
+		Expect(d.SetEnum("format", "xml", formats)).To(MatchError(ContainSubstring(`invalid value "xml"`)))
+		Expect(d.SetEnumSlice("formats", []string{"json", "xml"}, formats)).NotTo(Succeed())
+		Expect(d.SetText("level", slog.LevelWarn)).To(Succeed())
+
+		Expect(d.GetAddr("addr")).To(Equal(netip.MustParseAddr("fe80::1%eth0")))
+		Expect(d.GetPrefixSlice("prefixes")).To(ConsistOf(netip.MustParsePrefix("10.0.0.0/8")))
+		Expect(d.GetAddrPort("addrport")).To(Equal(netip.MustParseAddrPort("[::1]:80")))
+		Expect(d.GetTime("time")).To(Equal(now))
+		Expect(d.GetLocation("tz")).To(Equal(time.UTC))
+		Expect(d.GetURLSlice("urls")).To(ConsistOf(u))
+		Expect(Successful(d.GetRegexp("re")).String()).To(Equal(`^a+$`))
+		Expect(d.GetEnum("format", formats)).To(Equal("text"))
+		Expect(d.Exists("formats")).To(BeFalse())
+		Expect(GetText[slog.Level](d, "level")).To(Equal(slog.LevelWarn))
+	})
+
+	It("sets values through views", func() {
+		d := load(`
+server:
+  port: 80
+`)
+		live := d.LiveSub("server")
+		Expect(live.SetInt("port", 8080)).To(Succeed())
+		Expect(d.GetInt("server.port")).To(Equal(8080))
+		Expect(live.Int("port")).To(Equal(8080))
+
+		snapshot := d.Sub("server")
+		Expect(snapshot.SetInt("port", 443)).To(Succeed())
+		Expect(snapshot.GetInt("port")).To(Equal(443))
+		Expect(d.GetInt("server.port")).To(Equal(8080))
+	})
+
+	It("replaces deprecated and unnormalized settings", func() {
+		d := New(koanf.New("."),
+			WithAlias("server.addr", "http.addr"),
+			WithNormalizeFunc(NormalizeDashes),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		Expect(d.Load(mapProvider{
+			"server":    map[string]any{"addr": ":80", "Port": 80},
+			"max_conns": 42,
+		}, nil)).To(Succeed())
+
+		Expect(d.SetString("http.addr", ":8080")).To(Succeed())
+		Expect(d.GetString("http.addr")).To(Equal(":8080"))
+		Expect(d.Exists("server.addr")).To(BeFalse())
+		Expect(d.GetInt("server.port")).To(Equal(80))
+
+		Expect(d.SetInt("max-conns", 666)).To(Succeed())
+		Expect(d.GetInt("max-conns")).To(Equal(666))
+		Expect(d.Exists("max_conns")).To(BeFalse())
+
+		live := d.LiveSub("server")
+		Expect(live.SetInt("port", 8080)).To(Succeed())
+		Expect(live.GetInt("port")).To(Equal(8080))
+		Expect(live.Keys()).To(ConsistOf("port"))
+		Expect(d.Keys()).To(ConsistOf("http.addr", "server.port", "max-conns"))
+	})
+
+	It("atomically saves configurations", func() {
+		tmp := GinkgoT().TempDir()
+		name := writeFile(tmp, "conf.yaml", "old: true\n")
+		Expect(os.Chmod(name, 0o600)).To(Succeed())
+
+		d := New(koanf.New("."))
+		Expect(d.SetDuration("server.timeout", 90*time.Second)).To(Succeed())
+		Expect(d.SetIPNet("server.net", net.IPNet{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)})).To(Succeed())
+		Expect(d.SaveFile(name, yaml.Parser())).To(Succeed())
+		Expect(Successful(os.Stat(name)).Mode().Perm()).To(Equal(os.FileMode(0o600)))
+		Expect(Successful(os.ReadDir(tmp))).To(HaveLen(1))
+
+		saved := New(koanf.New("."))
+		Expect(saved.LoadFile(name, yaml.Parser())).To(Succeed())
+		Expect(saved.Exists("old")).To(BeFalse())
+		Expect(saved.GetDuration("server.timeout")).To(Equal(90 * time.Second))
+
+		jsonName := filepath.Join(tmp, "conf.json")
+		Expect(d.Sub("server").SaveFile(jsonName, jsonParser{})).To(Succeed())
+		Expect(os.ReadFile(jsonName)).To(MatchJSON(`{"net":"10.0.0.0/8","timeout":"1m30s"}`))
+
+		Expect(d.SaveFile(filepath.Join(tmp, "nada", "conf.yaml"), yaml.Parser())).NotTo(Succeed())
+	})
+
+})
@@ -0,0 +1,111 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package sub
+
+import (
+	"slices"
+	"strings"
+)
+
+// Router maps a flat key, such as a CLI flag name, to its full destination key
+// path, including the final key name. A nil or empty path indicates that the
+// key is to be merged unchanged at the root of the destination map.
+type Router func(key string) []string
+
+// Route describes flat keys that start with Prefix and that belong under the
+// destination Path, with the prefix stripped off. For instance, the Route
+// {Prefix: "db-", Path: []string{"db"}} moves “db-host” to “db.host”.
+type Route struct {
+	Prefix string
+	Path   []string
+}
+
+// Prefixes returns a Router for the specified prefix routes. In case multiple
+// routes match the same flat key, the route with the longest prefix wins.
+// Flat keys not matching any route are left at the root.
+func Prefixes(routes ...Route) Router {
+	routes = slices.Clone(routes)
+	slices.SortStableFunc(routes, func(a, b Route) int {
+		return len(b.Prefix) - len(a.Prefix)
+	})
+	return func(key string) []string {
+		for _, route := range routes {
+			name, ok := strings.CutPrefix(key, route.Prefix)
+			if !ok || name == "" {
+				continue
+			}
+			return append(slices.Clone(route.Path), name)
+		}
+		return nil
+	}
+}
+
+// Mapping returns a Router for the specified mapping table of flat keys to
+// their full destination key paths. Flat keys not in the mapping table are
+// left at the root.
+func Mapping(table map[string][]string) Router {
+	return func(key string) []string {
+		return table[key]
+	}
+}
+
+// MergeRouted returns a map merge function that merges each of the top-level
+// keys in its src map into its dest map at the destination path returned by
+// the specified router, mutating the destination map. For instance,
+// MergeRouted allows merging in CLI flags such as “--db-host” and
+// “--http-listen” at “db.host” and “http.listen” in a single pass.
+//
+// The returned merge function has the same properties as the merge function
+// returned by [Merge].
+func MergeRouted(router Router) func(src, dest map[string]any) {
+	return func(src, dest map[string]any) {
+		// Group the keys by their destination paths (sans final key names),
+		// so that we then can merge each group in one go using the standard
+		// submerge.
+		type group struct {
+			path []string
+			src  map[string]any
+		}
+		groups := map[string]*group{}
+		var order []string
+		for key, value := range src {
+			path := router(key)
+			if len(path) == 0 {
+				path = []string{key}
+			}
+			parent := path[:len(path)-1]
+			id := strings.Join(parent, "\x00")
+			g, ok := groups[id]
+			if !ok {
+				g = &group{path: parent, src: map[string]any{}}
+				groups[id] = g
+				order = append(order, id)
+			}
+			g.src[path[len(path)-1]] = value
+		}
+		// Merge shallower groups first, so that deeper groups don't get
+		// busted by shallower groups replacing non-map values.
+		slices.SortFunc(order, func(a, b string) int {
+			if d := len(groups[a].path) - len(groups[b].path); d != 0 {
+				return d
+			}
+			return strings.Compare(a, b)
+		})
+		for _, id := range order {
+			g := groups[id]
+			Merge(g.path)(g.src, dest)
+		}
+	}
+}
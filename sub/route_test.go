@@ -0,0 +1,80 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package sub
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("routing flat keys", func() {
+
+	It("routes by longest prefix", func() {
+		router := Prefixes(
+			Route{Prefix: "db-", Path: []string{"db"}},
+			Route{Prefix: "db-replica-", Path: []string{"db", "replica"}},
+			Route{Prefix: "http-", Path: []string{"http"}},
+		)
+		Expect(router("db-host")).To(Equal([]string{"db", "host"}))
+		Expect(router("db-replica-host")).To(Equal([]string{"db", "replica", "host"}))
+		Expect(router("http-listen")).To(Equal([]string{"http", "listen"}))
+		Expect(router("db-")).To(BeNil())
+		Expect(router("verbose")).To(BeNil())
+	})
+
+	It("routes by mapping table", func() {
+		router := Mapping(map[string][]string{
+			"addr": {"server", "listen"},
+		})
+		Expect(router("addr")).To(Equal([]string{"server", "listen"}))
+		Expect(router("verbose")).To(BeNil())
+	})
+
+	It("merges all groups in one pass", func() {
+		src := map[string]any{
+			"db-host":         "localhost",
+			"db-port":         5432,
+			"db-replica-host": "replica",
+			"http-listen":     ":8080",
+			"verbose":         true,
+		}
+		dst := map[string]any{
+			"db": map[string]any{
+				"user": "foo",
+			},
+			"http": "bust me",
+		}
+		MergeRouted(Prefixes(
+			Route{Prefix: "db-", Path: []string{"db"}},
+			Route{Prefix: "db-replica-", Path: []string{"db", "replica"}},
+			Route{Prefix: "http-", Path: []string{"http"}},
+		))(src, dst)
+		Expect(dst).To(Equal(map[string]any{
+			"db": map[string]any{
+				"user": "foo",
+				"host": "localhost",
+				"port": 5432,
+				"replica": map[string]any{
+					"host": "replica",
+				},
+			},
+			"http": map[string]any{
+				"listen": ":8080",
+			},
+			"verbose": true,
+		}))
+	})
+
+})
@@ -0,0 +1,46 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import "encoding"
+
+// TextUnmarshaler constrains *T to implement [encoding.TextUnmarshaler].
+type TextUnmarshaler[T any] interface {
+	*T
+	encoding.TextUnmarshaler
+}
+
+// GetText returns the value of a configuration setting with the given name,
+// decoded into type T using its [encoding.TextUnmarshaler] implementation. This
+// works out of the box with many standard and third-party types, such as
+// slog.Level, big.Int, and time.Time. Values that the configuration parser
+// already returned as type T are returned as-is. For instance:
+//
+//	level, err := deafadder.GetText[slog.Level](d, "log.level")
+func GetText[T any, PT TextUnmarshaler[T]](d *DeafAdder, path string) (v T, err error) {
+	return convert(d, path, typedOrTextual(unmarshalText[T, PT]))
+}
+
+// GetTextSlice returns the []T value of a configuration setting with the given
+// name, decoding each element using the [encoding.TextUnmarshaler]
+// implementation of type T.
+func GetTextSlice[T any, PT TextUnmarshaler[T]](d *DeafAdder, path string) (v []T, err error) {
+	return convertSlice(d, path, typedOrTextual(unmarshalText[T, PT]))
+}
+
+func unmarshalText[T any, PT TextUnmarshaler[T]](s string) (v T, err error) {
+	err = PT(&v).UnmarshalText([]byte(s))
+	return v, err
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"log/slog"
+	"math/big"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("text unmarshalling", func() {
+
+	const s = `
+level: warn
+levels:
+  - debug
+  - error+2
+bad-levels:
+  - info
+  - chatty
+answer: '123456789012345678901234567890'
+addr: fe80::1
+`
+
+	It("decodes values", func() {
+		d := load(s)
+		Expect(GetText[slog.Level](d, "level")).To(Equal(slog.LevelWarn))
+		Expect(GetTextSlice[slog.Level](d, "levels")).To(Equal(
+			[]slog.Level{slog.LevelDebug, slog.LevelError + 2}))
+		answer := Successful(GetText[big.Int](d, "answer"))
+		Expect(answer.String()).To(Equal("123456789012345678901234567890"))
+		Expect(GetText[netip.Addr](d, "addr")).To(Equal(netip.MustParseAddr("fe80::1")))
+	})
+
+	It("reports errors", func() {
+		d := load(s)
+		Expect(GetText[slog.Level](d, "nada")).Error().To(
+			MatchError(ContainSubstring("no such configuration setting nada")))
+		_, err := GetText[netip.Addr](d, "level")
+		Expect(err).To(MatchError(ContainSubstring("invalid value for configuration setting level")))
+		Expect(err).To(BeAssignableToTypeOf(&ConversionError{}))
+		Expect(GetTextSlice[slog.Level](d, "level")).Error().To(
+			MatchError(ContainSubstring("must be slice")))
+		Expect(GetTextSlice[slog.Level](d, "bad-levels")).Error().To(
+			MatchError(ContainSubstring("invalid value for configuration setting bad-levels[1]")))
+	})
+
+})
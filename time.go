@@ -0,0 +1,89 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetTime returns the time.Time value of a configuration setting with the
+// given name. Textual values are parsed in RFC3339 format, and otherwise using
+// the optionally specified layouts, in the order given. Values that the
+// configuration parser already returned as time.Time, such as YAML timestamps,
+// are returned as-is.
+func (d *DeafAdder) GetTime(path string, layouts ...string) (v time.Time, err error) {
+	return convert(d, path, typedOrTextual(timeParser(layouts)))
+}
+
+// GetTimeSlice returns the []time.Time value of a configuration setting with
+// the given name, parsing textual elements as described in [DeafAdder.GetTime].
+func (d *DeafAdder) GetTimeSlice(path string, layouts ...string) (v []time.Time, err error) {
+	return convertSlice(d, path, typedOrTextual(timeParser(layouts)))
+}
+
+// GetLocation returns the *time.Location value of a configuration setting
+// with the given name, such as “Europe/Berlin” or “UTC”, using the IANA Time
+// Zone database. In contrast to time.LoadLocation, empty names are rejected
+// instead of silently meaning UTC.
+func (d *DeafAdder) GetLocation(path string) (v *time.Location, err error) {
+	return parse(d, path, func(s string) (*time.Location, error) {
+		name := strings.TrimSpace(s)
+		if name == "" {
+			return nil, errors.New("empty time zone name")
+		}
+		return time.LoadLocation(name)
+	})
+}
+
+// timeParser returns a parse function that parses textual time values in
+// RFC3339 format or any of the specified layouts.
+func timeParser(layouts []string) func(string) (time.Time, error) {
+	return func(s string) (time.Time, error) {
+		s = strings.TrimSpace(s)
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as RFC3339 time or using layouts %q",
+			s, layouts)
+	}
+}
+
+// SetTime sets the configuration setting with the given name to the specified
+// time.Time value in RFC3339 format, with fractional seconds as necessary.
+func (d *DeafAdder) SetTime(path string, v time.Time) error {
+	return d.set(path, formatTime(v))
+}
+
+// SetTimeSlice sets the configuration setting with the given name to the
+// specified []time.Time value, see also [DeafAdder.SetTime].
+func (d *DeafAdder) SetTimeSlice(path string, v []time.Time) error {
+	return setStrings(d, path, v, formatTime)
+}
+
+// SetLocation sets the configuration setting with the given name to the name
+// of the specified *time.Location.
+func (d *DeafAdder) SetLocation(path string, v *time.Location) error {
+	return d.set(path, v.String())
+}
+
+func formatTime(t time.Time) string { return t.Format(time.RFC3339Nano) }
@@ -0,0 +1,75 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"time"
+	_ "time/tzdata"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("time accessors", func() {
+
+	const s = `
+timestamp: 2025-01-02T03:04:05Z
+rfc3339: '2025-01-02T03:04:05.123+01:00'
+custom: '02.01.2025 03:04'
+times:
+  - 2025-01-02T03:04:05Z
+  - '2025-01-03T03:04:05Z'
+  - '03.01.2025 03:04'
+bad-times:
+  - '2025-01-03T03:04:05Z'
+  - 'yesterday'
+zone: Europe/Berlin
+bad-zone: Middle/Earth
+no-zone: ""
+`
+
+	It("returns times", func() {
+		d := load(s)
+		Expect(d.GetTime("timestamp")).To(Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+		Expect(d.GetTime("rfc3339")).To(BeTemporally("==",
+			time.Date(2025, 1, 2, 2, 4, 5, 123000000, time.UTC)))
+		Expect(d.GetTime("custom", "2006-01-02", "02.01.2006 15:04")).To(Equal(
+			time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)))
+		Expect(d.GetTimeSlice("times", "02.01.2006 15:04")).To(Equal([]time.Time{
+			time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			time.Date(2025, 1, 3, 3, 4, 5, 0, time.UTC),
+			time.Date(2025, 1, 3, 3, 4, 0, 0, time.UTC),
+		}))
+	})
+
+	It("reports invalid times", func() {
+		d := load(s)
+		Expect(d.GetTime("custom")).Error().To(MatchError(ContainSubstring(
+			`invalid value for configuration setting custom: cannot parse "02.01.2025 03:04"`)))
+		Expect(d.GetTimeSlice("bad-times")).Error().To(MatchError(ContainSubstring("bad-times[1]")))
+	})
+
+	It("returns locations", func() {
+		d := load(s)
+		loc := Successful(d.GetLocation("zone"))
+		Expect(loc.String()).To(Equal("Europe/Berlin"))
+		Expect(d.GetLocation("bad-zone")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad-zone")))
+		Expect(d.GetLocation("no-zone")).Error().To(MatchError(
+			"invalid value for configuration setting no-zone: empty time zone name"))
+	})
+
+})
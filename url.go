@@ -0,0 +1,95 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// URLCheck checks a parsed URL, returning an error if the URL doesn't pass the
+// check.
+type URLCheck func(u *url.URL) error
+
+// RequireScheme returns a [URLCheck] that requires URLs to use one of the
+// specified schemes, such as “https”. Schemes are matched case-insensitively.
+func RequireScheme(schemes ...string) URLCheck {
+	return func(u *url.URL) error {
+		if slices.ContainsFunc(schemes, func(scheme string) bool {
+			return strings.EqualFold(scheme, u.Scheme)
+		}) {
+			return nil
+		}
+		return fmt.Errorf("URL %q must use scheme %s", u, strings.Join(schemes, " or "))
+	}
+}
+
+// RequireAbsolute returns a [URLCheck] that requires URLs to be absolute, that
+// is, to have a scheme.
+func RequireAbsolute() URLCheck {
+	return func(u *url.URL) error {
+		if !u.IsAbs() {
+			return fmt.Errorf("URL %q must be absolute", u)
+		}
+		return nil
+	}
+}
+
+// GetURL returns the *url.URL value of a configuration setting with the given
+// name, additionally passing the URL through the optionally specified checks.
+func (d *DeafAdder) GetURL(path string, checks ...URLCheck) (v *url.URL, err error) {
+	return parse(d, path, urlParser(checks))
+}
+
+// GetURLSlice returns the []*url.URL value of a configuration setting with the
+// given name, additionally passing each URL through the optionally specified
+// checks.
+func (d *DeafAdder) GetURLSlice(path string, checks ...URLCheck) (v []*url.URL, err error) {
+	return parseSlice(d, path, urlParser(checks))
+}
+
+func urlParser(checks []URLCheck) func(string) (*url.URL, error) {
+	return func(s string) (*url.URL, error) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, errors.New("empty URL")
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range checks {
+			if err := check(u); err != nil {
+				return nil, err
+			}
+		}
+		return u, nil
+	}
+}
+
+// SetURL sets the configuration setting with the given name to the specified
+// *url.URL value.
+func (d *DeafAdder) SetURL(path string, v *url.URL) error {
+	return d.set(path, v.String())
+}
+
+// SetURLSlice sets the configuration setting with the given name to the
+// specified []*url.URL value.
+func (d *DeafAdder) SetURLSlice(path string, v []*url.URL) error {
+	return setStrings(d, path, v, (*url.URL).String)
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("URL accessors", func() {
+
+	const s = `
+endpoint: https://example.org:8443/api
+relative: /api/v1
+bad: "http://[::1"
+empty: " "
+endpoints:
+  - https://example.org
+  - http://example.com
+`
+
+	It("returns URLs", func() {
+		d := load(s)
+		u := Successful(d.GetURL("endpoint", RequireAbsolute(), RequireScheme("https")))
+		Expect(u.Host).To(Equal("example.org:8443"))
+		Expect(u.Path).To(Equal("/api"))
+		Expect(d.GetURL("relative")).To(HaveField("Path", "/api/v1"))
+		us := Successful(d.GetURLSlice("endpoints", RequireScheme("HTTP", "https")))
+		Expect(us).To(HaveLen(2))
+		Expect(us[1].Host).To(Equal("example.com"))
+	})
+
+	It("reports invalid URLs", func() {
+		d := load(s)
+		Expect(d.GetURL("bad")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad")))
+		Expect(d.GetURL("relative", RequireAbsolute())).Error().To(MatchError(ContainSubstring(
+			`invalid value for configuration setting relative: URL "/api/v1" must be absolute`)))
+		Expect(d.GetURLSlice("endpoints", RequireScheme("https"))).Error().To(MatchError(ContainSubstring(
+			`endpoints[1]: URL "http://example.com" must use scheme https`)))
+		Expect(d.GetURL("empty")).Error().To(MatchError(
+			"invalid value for configuration setting empty: empty URL"))
+	})
+
+})
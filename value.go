@@ -0,0 +1,161 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// GetValue sets the specified [pflag.Value] from the configuration setting
+// with the given name, so that user-defined flag value types can be used with
+// configuration settings too. Scalar configuration values are passed in their
+// textual representation to the flag value's Set method. List configuration
+// values are passed to the flag value's Replace method if it is a
+// [pflag.SliceValue], and otherwise as a comma-separated list to its Set
+// method.
+func (d *DeafAdder) GetValue(path string, value pflag.Value) error {
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return err
+	}
+	isSlice := reflect.TypeOf(configValue).Kind() == reflect.Slice
+	if err := setValue(value, configValue, isSlice); err != nil {
+		return d.redact(path, &ConversionError{Path: d.abs(path), Value: configValue, Err: err})
+	}
+	return nil
+}
+
+// registry maps types to getters returning values of these types.
+var registry = struct {
+	sync.RWMutex
+	getters map[reflect.Type]func(d *DeafAdder, path string) (any, error)
+}{
+	getters: map[reflect.Type]func(d *DeafAdder, path string) (any, error){},
+}
+
+// RegisterValue registers a factory for type T, returning new [pflag.Value]
+// objects that store their values in the variable p points to. [Get] then uses
+// the registered factory in order to retrieve configuration setting values of
+// type T. Registering another factory for the same type T replaces any
+// previously registered factory or built-in type. Additionally, [Format] uses
+// the registered factory in order to format values of type T.
+func RegisterValue[T any](factory func(p *T) pflag.Value) {
+	register(func(d *DeafAdder, path string) (v T, err error) {
+		err = d.GetValue(path, factory(&v))
+		return v, err
+	})
+	registerFormat(func(v T) (any, error) {
+		value := factory(&v)
+		if sv, ok := value.(pflag.SliceValue); ok {
+			return sv.GetSlice(), nil
+		}
+		return value.String(), nil
+	})
+}
+
+// Get returns the value of type T of a configuration setting with the given
+// name. Get supports the types of the typed accessors, such as int,
+// time.Duration, and netip.Addr, as well as all user-defined types registered
+// using [RegisterValue]. As Get resolves accessors solely by type, int always
+// means [DeafAdder.GetInt] and not [DeafAdder.GetCount], []string always means
+// [DeafAdder.GetStringSlice], and []byte isn't supported out of the box.
+func Get[T any](d *DeafAdder, path string) (v T, err error) {
+	get, err := getter(reflect.TypeFor[T]())
+	if err != nil {
+		return v, err
+	}
+	value, err := get(d, path)
+	if err != nil {
+		return v, err
+	}
+	return value.(T), nil
+}
+
+// getter returns the registered getter for the specified type.
+func getter(typ reflect.Type) (func(d *DeafAdder, path string) (any, error), error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	get, ok := registry.getters[typ]
+	if !ok {
+		return nil, fmt.Errorf("no flag value registered for type %s", typ)
+	}
+	return get, nil
+}
+
+// register the specified typed getter for its type T.
+func register[T any](get func(d *DeafAdder, path string) (T, error)) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.getters[reflect.TypeFor[T]()] = func(d *DeafAdder, path string) (any, error) {
+		return get(d, path)
+	}
+}
+
+func init() {
+	register((*DeafAdder).GetBool)
+	register((*DeafAdder).GetByteSize)
+	register((*DeafAdder).GetByteSizeSlice)
+	register((*DeafAdder).GetDuration)
+	register((*DeafAdder).GetDurationSlice)
+	register((*DeafAdder).GetFloat32)
+	register((*DeafAdder).GetFloat32Slice)
+	register((*DeafAdder).GetFloat64)
+	register((*DeafAdder).GetFloat64Slice)
+	register((*DeafAdder).GetInt)
+	register((*DeafAdder).GetIntSlice)
+	register((*DeafAdder).GetInt8)
+	register((*DeafAdder).GetInt16)
+	register((*DeafAdder).GetInt32)
+	register((*DeafAdder).GetInt32Slice)
+	register((*DeafAdder).GetInt64)
+	register((*DeafAdder).GetInt64Slice)
+	register((*DeafAdder).GetIP)
+	register((*DeafAdder).GetIPSlice)
+	register((*DeafAdder).GetIPMask)
+	register((*DeafAdder).GetIPNet)
+	register((*DeafAdder).GetIPNetSlice)
+	register((*DeafAdder).GetString)
+	register((*DeafAdder).GetStringSlice)
+	register((*DeafAdder).GetUint)
+	register((*DeafAdder).GetUintSlice)
+	register((*DeafAdder).GetUint8)
+	register((*DeafAdder).GetUint16)
+	register((*DeafAdder).GetUint32)
+	register((*DeafAdder).GetUint64)
+
+	register((*DeafAdder).GetAddr)
+	register((*DeafAdder).GetAddrSlice)
+	register((*DeafAdder).GetPrefix)
+	register((*DeafAdder).GetPrefixSlice)
+	register((*DeafAdder).GetAddrPort)
+	register((*DeafAdder).GetAddrPortSlice)
+
+	register(func(d *DeafAdder, path string) (time.Time, error) { return d.GetTime(path) })
+	register(func(d *DeafAdder, path string) ([]time.Time, error) { return d.GetTimeSlice(path) })
+	register((*DeafAdder).GetLocation)
+	register((*DeafAdder).GetSchedule)
+
+	register(func(d *DeafAdder, path string) (*url.URL, error) { return d.GetURL(path) })
+	register(func(d *DeafAdder, path string) ([]*url.URL, error) { return d.GetURLSlice(path) })
+	register((*DeafAdder).GetRegexp)
+	register((*DeafAdder).GetRegexpSlice)
+	register((*DeafAdder).GetSecret)
+}
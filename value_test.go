@@ -0,0 +1,110 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type logLevel int
+
+func (l *logLevel) String() string { return [...]string{"debug", "info"}[*l] }
+func (l *logLevel) Type() string   { return "logLevel" }
+func (l *logLevel) Set(s string) error {
+	switch s {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("invalid log level " + s)
+	}
+	return nil
+}
+
+type hostList []string
+
+func (h *hostList) String() string { return strings.Join(*h, ",") }
+func (h *hostList) Type() string   { return "hosts" }
+func (h *hostList) Set(s string) error {
+	*h = strings.Split(s, ",")
+	return nil
+}
+
+type hostSliceList struct{ hostList }
+
+func (h *hostSliceList) Append(s string) error { h.hostList = append(h.hostList, s); return nil }
+func (h *hostSliceList) GetSlice() []string    { return h.hostList }
+func (h *hostSliceList) Replace(s []string) error {
+	h.hostList = append([]string{"replaced"}, s...)
+	return nil
+}
+
+var _ pflag.SliceValue = (*hostSliceList)(nil)
+
+var _ = Describe("custom flag values", func() {
+
+	const s = `
+level: info
+bad-level: chatty
+hosts:
+  - foo
+  - bar
+duration: 42s
+addr: 127.0.0.1
+`
+
+	It("sets custom flag values", func() {
+		d := load(s)
+		var level logLevel
+		Expect(d.GetValue("level", &level)).To(Succeed())
+		Expect(level).To(Equal(logLevel(1)))
+		err := d.GetValue("bad-level", &level)
+		Expect(err).To(MatchError(ContainSubstring(
+			"invalid value for configuration setting bad-level: invalid log level chatty")))
+		Expect(err).To(BeAssignableToTypeOf(&ConversionError{}))
+		Expect(d.GetValue("nada", &level)).To(MatchError(ContainSubstring("no such")))
+
+		var hosts hostList
+		Expect(d.GetValue("hosts", &hosts)).To(Succeed())
+		Expect(hosts).To(Equal(hostList{"foo", "bar"}))
+
+		var shosts hostSliceList
+		Expect(d.GetValue("hosts", &shosts)).To(Succeed())
+		Expect(shosts.hostList).To(Equal(hostList{"replaced", "foo", "bar"}))
+	})
+
+	It("gets values of built-in and registered types", func() {
+		d := load(s)
+		Expect(Get[time.Duration](d, "duration")).To(Equal(42 * time.Second))
+		Expect(Get[netip.Addr](d, "addr")).To(Equal(netip.MustParseAddr("127.0.0.1")))
+
+		Expect(Get[logLevel](d, "level")).Error().To(
+			MatchError("no flag value registered for type deafadder.logLevel"))
+		RegisterValue(func(p *logLevel) pflag.Value { return p })
+		Expect(Get[logLevel](d, "level")).To(Equal(logLevel(1)))
+		Expect(Format(logLevel(1))).To(Equal("info"))
+		Expect(Get[logLevel](d, "bad-level")).Error().To(MatchError(ContainSubstring("chatty")))
+	})
+
+})
@@ -0,0 +1,258 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Sub returns a DeafAdder view of the configuration subtree at the given path,
+// sharing the settings of this DeafAdder object, such as strict IP conversion,
+// aliases, and provenance. The view's accessors take paths relative to the
+// subtree, but report absolute paths in errors. The view works on a snapshot
+// of the configuration taken at the time of calling Sub, so later changes to
+// this DeafAdder object's configuration don't show up in the view; use
+// [DeafAdder.LiveSub] instead to see later changes.
+func (d *DeafAdder) Sub(path string) *DeafAdder {
+	root := &DeafAdder{
+		Koanf: d.top().Copy(),
+		opts:  d.settings(),
+	}
+	return root.view(d.abs(path))
+}
+
+// LiveSub returns a DeafAdder view of the configuration subtree at the given
+// path, similar to [DeafAdder.Sub]. In contrast to Sub, the view's accessors
+// always see the current configuration of this DeafAdder object, and so do
+// Get, Exists, Keys, KeyMap, All, Raw, Walk, Children, Leaves, Dump, Diff, and
+// SaveFile. However, the typed getters of the embedded koanf.Koanf object, such
+// as String and Int, operate on a snapshot taken at the time of calling
+// LiveSub; use the DeafAdder accessors instead, such as [DeafAdder.GetString].
+func (d *DeafAdder) LiveSub(path string) *DeafAdder {
+	return d.top().view(d.abs(path))
+}
+
+// GetSubSlice returns the list of sub-configurations (maps) of the
+// configuration setting with the given name as DeafAdder views, see also
+// [DeafAdder.Sub]. The views' paths are of the form “listeners[2]”, so errors
+// report the paths of settings in list elements as “listeners[2].port”. As
+// with Sub, interpolation references, aliases, and secrets use absolute paths,
+// and the views work on a snapshot of the configuration.
+func (d *DeafAdder) GetSubSlice(path string) ([]*DeafAdder, error) {
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	elements, ok := configValue.([]any)
+	if !ok {
+		return nil, fmt.Errorf("value for configuration setting %s must be slice", d.abs(path))
+	}
+	snapshot := d.Sub(path)
+	subs := make([]*DeafAdder, len(elements))
+	for idx, element := range elements {
+		name := fmt.Sprintf("%s[%d]", d.abs(path), idx)
+		if _, ok := element.(map[string]any); !ok {
+			return nil, d.redact(path, &ConversionError{Path: name, Value: element, Err: errors.New("not a map")})
+		}
+		subs[idx] = snapshot.root.view(name)
+	}
+	return subs, nil
+}
+
+// GetSubMap returns the map of sub-configurations (maps) of the configuration
+// setting with the given name as DeafAdder views, indexed by their keys, see
+// also [DeafAdder.Sub].
+func (d *DeafAdder) GetSubMap(path string) (map[string]*DeafAdder, error) {
+	configValue, err := d.lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	mp, ok := configValue.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("value for configuration setting %s must be map", d.abs(path))
+	}
+	snapshot := d.Sub(path)
+	subs := make(map[string]*DeafAdder, len(mp))
+	for key, element := range mp {
+		if _, ok := element.(map[string]any); !ok {
+			return nil, d.redact(path, &ConversionError{
+				Path:  d.abs(path) + d.Delim() + key,
+				Value: element,
+				Err:   errors.New("not a map"),
+			})
+		}
+		subs[key] = snapshot.root.view(snapshot.abs(key))
+	}
+	return subs, nil
+}
+
+// view returns a view of the configuration subtree at the specified absolute
+// path of this (top-level) DeafAdder object.
+func (d *DeafAdder) view(path string) *DeafAdder {
+	return &DeafAdder{
+		Koanf:  d.tree(path),
+		opts:   d.settings(),
+		root:   d,
+		prefix: path,
+	}
+}
+
+// tree returns a copy of the configuration subtree at the specified absolute
+// path, which might include list element indices, such as “listeners[2]”.
+func (d *DeafAdder) tree(path string) *koanf.Koanf {
+	if !strings.Contains(path, "[") {
+		return d.Cut(path)
+	}
+	k := koanf.New(d.Delim())
+	if value, _ := d.indexed(path); value != nil {
+		if mp, ok := value.(map[string]any); ok {
+			_ = k.Load(mapProvider(mp), nil)
+		}
+	}
+	return k
+}
+
+// resolve returns the raw value for the specified absolute path, similar to
+// raw, but additionally resolving paths with list element indices, such as
+// “listeners[2].port”. It returns nil if there is no such value.
+func (d *DeafAdder) resolve(path string) (any, error) {
+	value, err := d.raw(path)
+	if err != nil || value != nil || !strings.Contains(path, "[") {
+		return value, err
+	}
+	return d.indexed(path)
+}
+
+// indexed returns the value at the specified absolute path with list element
+// indices, such as “listeners[2].port”, or nil if there is no such value. The
+// list itself is looked up taking aliases and normalization into account.
+func (d *DeafAdder) indexed(path string) (any, error) {
+	segments := strings.Split(path, d.Delim())
+	first := slices.IndexFunc(segments, func(segment string) bool {
+		return strings.HasSuffix(segment, "]")
+	})
+	if first < 0 {
+		return nil, nil
+	}
+	var value any
+	for idx, segment := range segments[first:] {
+		name, indices, ok := splitIndices(segment)
+		if !ok {
+			return nil, nil
+		}
+		if idx == 0 {
+			var err error
+			value, err = d.raw(strings.Join(append(slices.Clone(segments[:first]), name), d.Delim()))
+			if err != nil {
+				return nil, err
+			}
+		} else if mp, ok := value.(map[string]any); ok {
+			value = mp[name]
+		} else {
+			return nil, nil
+		}
+		for _, index := range indices {
+			elements, ok := value.([]any)
+			if !ok || index >= len(elements) {
+				return nil, nil
+			}
+			value = elements[index]
+		}
+	}
+	return value, nil
+}
+
+// splitIndices splits a path segment such as “listeners[2]” into its name and
+// list element indices.
+func splitIndices(segment string) (name string, indices []int, ok bool) {
+	name, rest, _ := strings.Cut(segment, "[")
+	if rest == "" {
+		return name, nil, !strings.Contains(segment, "]")
+	}
+	for _, index := range strings.Split(strings.TrimSuffix(rest, "]"), "][") {
+		idx, err := strconv.Atoi(index)
+		if err != nil || idx < 0 {
+			return "", nil, false
+		}
+		indices = append(indices, idx)
+	}
+	return name, indices, true
+}
+
+// current returns the configuration of this DeafAdder object; for views, this
+// is the current subtree of the top-level DeafAdder object.
+func (d *DeafAdder) current() *koanf.Koanf {
+	if d.root != nil {
+		return d.root.tree(d.prefix)
+	}
+	return d.Koanf
+}
+
+// Get returns the raw value of the configuration setting with the given name,
+// in the same way as [koanf.Koanf.Get] does, but routed through the top-level
+// DeafAdder object for views.
+func (d *DeafAdder) Get(path string) any { return d.current().Get(path) }
+
+// Exists returns true if the configuration setting with the given name exists,
+// in the same way as [koanf.Koanf.Exists] does, but routed through the
+// top-level DeafAdder object for views.
+func (d *DeafAdder) Exists(path string) bool { return d.current().Exists(path) }
+
+// Keys returns the sorted names of all configuration settings, in the same way
+// as [koanf.Koanf.Keys] does, but routed through the top-level DeafAdder object
+// for views.
+func (d *DeafAdder) Keys() []string { return d.current().Keys() }
+
+// KeyMap returns the map of all configuration setting and subtree names to
+// their name segments, in the same way as [koanf.Koanf.KeyMap] does, but routed
+// through the top-level DeafAdder object for views.
+func (d *DeafAdder) KeyMap() koanf.KeyMap { return d.current().KeyMap() }
+
+// All returns the flattened configuration, in the same way as
+// [koanf.Koanf.All] does, but routed through the top-level DeafAdder object for
+// views.
+func (d *DeafAdder) All() map[string]any { return d.current().All() }
+
+// Raw returns a copy of the nested configuration, in the same way as
+// [koanf.Koanf.Raw] does, but routed through the top-level DeafAdder object for
+// views.
+func (d *DeafAdder) Raw() map[string]any { return d.current().Raw() }
+
+// top returns the top-level DeafAdder object this DeafAdder object is a view
+// of, or this DeafAdder object itself if it isn't a view.
+func (d *DeafAdder) top() *DeafAdder {
+	if d.root != nil {
+		return d.root
+	}
+	return d
+}
+
+// abs returns the absolute path for the specified path relative to this
+// DeafAdder object, taking views into account.
+func (d *DeafAdder) abs(path string) string {
+	switch {
+	case d.prefix == "":
+		return path
+	case path == "":
+		return d.prefix
+	}
+	return d.prefix + d.Delim() + path
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package deafadder
+
+import (
+	"maps"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/thediveo/success"
+)
+
+var _ = Describe("subtree views", func() {
+
+	const conf = `
+server:
+  http:
+    port: 8080
+    addr: 127.0.0.1
+    bad: foobar
+`
+
+	It("scopes accessors and reports absolute paths", func() {
+		d := load(conf)
+		d.opts.strictIP = true
+		server := d.Sub("server")
+		http := server.Sub("http")
+		Expect(http.GetInt("port")).To(Equal(8080))
+		Expect(http.GetIP("addr")).To(Equal(net.ParseIP("127.0.0.1")))
+		Expect(http.Keys()).To(ConsistOf("port", "addr", "bad"))
+		Expect(http.GetInt("bad")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting server.http.bad")))
+		Expect(http.GetInt("nada")).Error().To(MatchError(
+			"no such configuration setting server.http.nada"))
+		Expect(http.GetIP("port")).Error().To(HaveOccurred())
+	})
+
+	It("shares settings, provenance, and secrets", func() {
+		name := writeFile(GinkgoT().TempDir(), "conf.yaml", conf)
+		d := New(koanf.New("."), WithSecrets("server.http.bad"))
+		Expect(d.LoadFile(name, yaml.Parser())).To(Succeed())
+		http := d.Sub("server.http")
+		Expect(http.Origin("port")).To(Equal(name))
+		Expect(http.IsSecret("bad")).To(BeTrue())
+		Expect(http.GetSecret("addr")).Error().NotTo(HaveOccurred())
+		Expect(d.IsSecret("server.http.addr")).To(BeTrue())
+	})
+
+	It("snapshots or follows the parent", func() {
+		d := load(conf)
+		snapshot := d.Sub("server.http")
+		live := d.LiveSub("server.http")
+		Expect(d.Set("server.http.port", 1234)).To(Succeed())
+		Expect(snapshot.GetInt("port")).To(Equal(8080))
+		Expect(live.GetInt("port")).To(Equal(1234))
+		Expect(live.Sub("").GetInt("port")).To(Equal(1234))
+
+		Expect(d.Set("server.http.tls", true)).To(Succeed())
+		Expect(maps.Collect(live.Walk(""))).To(HaveKeyWithValue("tls", true))
+		Expect(maps.Collect(live.Children(""))).To(HaveKey("tls"))
+		Expect(maps.Collect(snapshot.Walk(""))).NotTo(HaveKey("tls"))
+		var b strings.Builder
+		Expect(live.Dump(&b, DumpJSON)).To(Succeed())
+		Expect(b.String()).To(ContainSubstring(`"tls": true`))
+
+		Expect(live.Get("port")).To(Equal(1234))
+		Expect(live.Exists("tls")).To(BeTrue())
+		Expect(live.Keys()).To(ContainElement("tls"))
+		Expect(live.KeyMap()).To(HaveKey("tls"))
+		Expect(live.All()).To(HaveKeyWithValue("port", 1234))
+		Expect(live.Raw()).To(HaveKeyWithValue("tls", true))
+		Expect(snapshot.Exists("tls")).To(BeFalse())
+		Expect(snapshot.Get("port")).To(Equal(8080))
+	})
+
+	It("returns lists and maps of sub-configurations", func() {
+		name := writeFile(GinkgoT().TempDir(), "conf.yaml", `
+listeners:
+  - port: 80
+  - port: 443
+    cert: tls/cert.pem
+  - port: foobar
+backends:
+  a:
+    port: 8080
+  b:
+    port: 8081
+notalist: 42
+mixed:
+  - port: 1
+  - 42
+`)
+		d := New(koanf.New("."))
+		Expect(d.LoadFile(name, yaml.Parser())).To(Succeed())
+
+		listeners := Successful(d.GetSubSlice("listeners"))
+		Expect(listeners).To(HaveLen(3))
+		Expect(listeners[1].GetInt("port")).To(Equal(443))
+		Expect(listeners[1].GetPath("cert")).To(Equal(
+			filepath.Join(filepath.Dir(name), "tls/cert.pem")))
+		Expect(listeners[2].GetInt("port")).Error().To(MatchError(ContainSubstring(
+			"invalid value for configuration setting listeners[2].port")))
+		Expect(listeners[0].GetInt("cert")).Error().To(MatchError(
+			"no such configuration setting listeners[0].cert"))
+		Expect(listeners[0].Sub("").GetInt("nada")).Error().To(MatchError(
+			"no such configuration setting listeners[0].nada"))
+
+		backends := Successful(d.GetSubMap("backends"))
+		Expect(backends).To(HaveKey("a"))
+		Expect(backends["b"].GetInt("port")).To(Equal(8081))
+		Expect(backends["a"].GetBool("port")).Error().To(MatchError(ContainSubstring(
+			"configuration setting backends.a.port")))
+
+		Expect(d.GetSubSlice("notalist")).Error().To(MatchError(
+			"value for configuration setting notalist must be slice"))
+		Expect(d.GetSubSlice("mixed")).Error().To(MatchError(ContainSubstring(
+			"configuration setting mixed[1]")))
+		Expect(d.GetSubMap("notalist")).Error().To(MatchError(
+			"value for configuration setting notalist must be map"))
+		Expect(d.GetSubMap("nada")).Error().To(HaveOccurred())
+	})
+
+	It("resolves list elements through the top-level configuration", func() {
+		d := New(koanf.New("."),
+			WithInterpolation(),
+			WithAlias("servers", "listeners"),
+			WithSecrets("listeners[0].password"))
+		Expect(d.Load(mapProvider{
+			"host": "example.org",
+			"servers": []any{
+				map[string]any{"addr": "${host}:80", "password": "sekret"},
+			},
+		}, nil)).To(Succeed())
+		listeners := Successful(d.GetSubSlice("listeners"))
+		Expect(listeners).To(HaveLen(1))
+		Expect(listeners[0].GetString("addr")).To(Equal("example.org:80"))
+		Expect(listeners[0].IsSecret("password")).To(BeTrue())
+		Expect(listeners[0].IsSecret("addr")).To(BeFalse())
+		Expect(d.GetString("listeners[0].addr")).To(Equal("example.org:80"))
+		Expect(d.GetInt("listeners[1].port")).Error().To(MatchError(
+			"no such configuration setting listeners[1].port"))
+	})
+
+})